@@ -0,0 +1,177 @@
+package xerrs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Definition - a declarative description of a domain error.
+// Declare definitions once at package level with Define and create a new
+// error for every occurrence with New or Wrap, so each one gets a fresh stack:
+//
+//	var ErrShipmentNotFound = xerrs.Define(xerrs.Definition{
+//		Code:       "shipment_not_found",
+//		Message:    "shipment %d not found",
+//		HTTPStatus: http.StatusNotFound,
+//		GRPCCode:   5, // codes.NotFound
+//		Severity:   xerrs.SeverityInfo,
+//		Mask:       errors.New("shipment not found"),
+//	})
+//
+//	return ErrShipmentNotFound.New(id)
+//
+// Errors created from a definition match it with errors.Is.
+type Definition struct {
+	// Code uniquely identifies the definition
+	Code string
+	// Message is the format of the error message, formatted with the arguments of New
+	Message string
+	// HTTPStatus is the HTTP status code returned for the error
+	HTTPStatus int
+	// GRPCCode is the numeric value of the google.golang.org/grpc/codes.Code returned for the error
+	GRPCCode int
+	// Severity of the error
+	Severity Severity
+	// Mask is set as the mask of every created error, see Mask
+	Mask error
+	// Description documents the error
+	Description string
+}
+
+var definitions = struct {
+	sync.RWMutex
+	byCode map[string]*Definition
+}{
+	byCode: make(map[string]*Definition),
+}
+
+// Define - registers a definition and returns it
+// It panics if the code is empty or already defined
+func Define(def Definition) *Definition {
+	if def.Code == "" {
+		panic("xerrs: Define called with an empty code")
+	}
+
+	definitions.Lock()
+	defer definitions.Unlock()
+
+	if _, ok := definitions.byCode[def.Code]; ok {
+		panic(fmt.Sprintf("xerrs: error code %q is already defined", def.Code))
+	}
+
+	d := &def
+	definitions.byCode[def.Code] = d
+	return d
+}
+
+// Definitions - returns every registered definition sorted by code
+func Definitions() []*Definition {
+	definitions.RLock()
+	defer definitions.RUnlock()
+
+	result := make([]*Definition, 0, len(definitions.byCode))
+	for _, def := range definitions.byCode {
+		result = append(result, def)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+
+	return result
+}
+
+// LookupDefinition - returns the definition registered with code
+func LookupDefinition(code string) (*Definition, bool) {
+	definitions.RLock()
+	defer definitions.RUnlock()
+
+	def, ok := definitions.byCode[code]
+	return def, ok
+}
+
+// Error - returns the definition code. It allows matching errors created from
+// the definition with errors.Is.
+func (d *Definition) Error() string {
+	return d.Code
+}
+
+// New - creates a new xerr with the definition message formatted with args
+// Option values in args are applied to the error instead of being formatted.
+// It will also set the stack.
+func (d *Definition) New(args ...interface{}) error {
+	args, opts := splitOptions(args)
+
+	return build(&xerr{
+		cause:    d.cause(args),
+		mask:     d.Mask,
+		format:   d.Message,
+		args:     args,
+		def:      d,
+		severity: d.Severity,
+	}, opts)
+}
+
+// Wrap - creates a new xerr which annotates err with the definition message
+// formatted with args
+// Option values in args are applied to the error instead of being formatted.
+// If err is nil then nil is returned
+// It will also set the stack.
+func (d *Definition) Wrap(err error, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	args, opts := splitOptions(args)
+
+	return build(&xerr{
+		cause:    err,
+		mask:     d.Mask,
+		msg:      d.cause(args).Error(),
+		format:   d.Message,
+		args:     args,
+		def:      d,
+		severity: d.Severity,
+	}, opts)
+}
+
+func (d *Definition) cause(args []interface{}) error {
+	if len(args) == 0 {
+		return errors.New(d.Message)
+	}
+
+	return fmt.Errorf(d.Message, args...)
+}
+
+// DefinitionOf - returns the definition of the outermost error in the chain
+// created from one
+// If there is none then nil is returned
+func DefinitionOf(err error) *Definition {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(*xerr); ok && x.def != nil {
+			return x.def
+		}
+	}
+
+	return nil
+}
+
+// Code - returns the code of the outermost error in the chain which has one,
+// set with WithCode or by the Definition it was created from
+// If err has no code then "" is returned
+func Code(err error) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(*xerr); ok {
+			if x.code != "" {
+				return x.code
+			}
+			if x.def != nil {
+				return x.def.Code
+			}
+		}
+	}
+
+	return ""
+}
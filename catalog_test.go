@@ -0,0 +1,100 @@
+package xerrs
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+var (
+	errTestShipmentNotFound = Define(Definition{
+		Code:       "test_shipment_not_found",
+		Message:    "shipment %d not found",
+		HTTPStatus: 404,
+		GRPCCode:   5,
+		Severity:   SeverityInfo,
+		Mask:       errors.New("shipment not found"),
+	})
+
+	errTestCarrierTimeout = Define(Definition{
+		Code:       "test_carrier_timeout",
+		Message:    "carrier timed out",
+		HTTPStatus: 504,
+		Severity:   SeverityError,
+	})
+)
+
+func TestDefinition(t *testing.T) {
+	err := errTestShipmentNotFound.New(123)
+
+	if got := Internal(err); got != "shipment 123 not found" {
+		t.Errorf("wrong internal message: want=%v got=%v", "shipment 123 not found", got)
+	}
+	if got := err.Error(); got != "shipment not found" {
+		t.Errorf("wrong error message: want=%v got=%v", "shipment not found", got)
+	}
+	if got := Template(err); got != "shipment %d not found" {
+		t.Errorf("wrong template: want=%v got=%v", "shipment %d not found", got)
+	}
+
+	wrapped := Wrap(err, "load")
+	if !errors.Is(wrapped, errTestShipmentNotFound) {
+		t.Error("expected wrapped error to match its definition")
+	}
+	if errors.Is(wrapped, errTestCarrierTimeout) {
+		t.Error("expected wrapped error not to match another definition")
+	}
+	if got := Code(wrapped); got != "test_shipment_not_found" {
+		t.Errorf("wrong code: want=%v got=%v", "test_shipment_not_found", got)
+	}
+	if def := DefinitionOf(wrapped); def == nil || def.HTTPStatus != 404 {
+		t.Errorf("wrong definition: got=%v", def)
+	}
+
+	if got := getLastPathPart(Stack(err)[0].Function); got != "xerrs.TestDefinition" {
+		t.Errorf("wrong stack top: want=%v got=%v", "xerrs.TestDefinition", got)
+	}
+
+	cause := errors.New("i/o timeout")
+	timeout := errTestCarrierTimeout.Wrap(cause)
+	if got := Internal(timeout); got != "carrier timed out: i/o timeout" {
+		t.Errorf("wrong internal message: want=%v got=%v", "carrier timed out: i/o timeout", got)
+	}
+	if !errors.Is(timeout, cause) || !errors.Is(timeout, errTestCarrierTimeout) {
+		t.Error("expected error to match its cause and definition")
+	}
+	if errTestCarrierTimeout.Wrap(nil) != nil {
+		t.Error("expected nil error")
+	}
+
+	if Code(errors.New("ABC")) != "" || DefinitionOf(nil) != nil {
+		t.Error("expected no code for basic error")
+	}
+}
+
+func TestDefinitions(t *testing.T) {
+	var codes []string
+	for _, def := range Definitions() {
+		codes = append(codes, def.Code)
+	}
+
+	if !sort.StringsAreSorted(codes) {
+		t.Errorf("expected definitions sorted by code, got=%v", codes)
+	}
+	for _, want := range []string{"test_carrier_timeout", "test_shipment_not_found"} {
+		if i := sort.SearchStrings(codes, want); i == len(codes) || codes[i] != want {
+			t.Errorf("missing definition %v: got=%v", want, codes)
+		}
+	}
+
+	if def, ok := LookupDefinition("test_carrier_timeout"); !ok || def != errTestCarrierTimeout {
+		t.Errorf("wrong definition: got=%v", def)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for duplicate code")
+		}
+	}()
+	Define(Definition{Code: "test_carrier_timeout"})
+}
@@ -0,0 +1,193 @@
+// Command xerrs-dedupe groups JSON encoded xerrs errors, one per line, by
+// normalized stack and message and prints every group with its count, first
+// and last seen time and the Details of its first error, largest group first.
+//
+// Errors are grouped by their Errorf format string, or by their cause with
+// numbers, hex values and UUIDs replaced and their root cause type, so errors
+// which differ only in IDs end up in one group.
+//
+// It reads the files given as arguments, or stdin. Text in front of the JSON
+// object, such as a log prefix, is skipped and so are lines without one:
+//
+//	xerrs-dedupe -ignore-lines -ignore-vendor errors.jsonl
+//	xerrs-parse app.log | xerrs-dedupe
+//
+// The time of an error is its creation time, which is encoded when tracking is
+// enabled, or a timestamp at the start of the log prefix in RFC 3339 or log
+// package format. Groups without either are reported by their position in the
+// input, e.g. "first seen #1, last seen #7".
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+type cluster struct {
+	count  int
+	first  *time.Time
+	last   *time.Time
+	record xerrs.Record
+	// firstIndex and lastIndex are the positions of the first and last error
+	// of the cluster in the input
+	firstIndex int
+	lastIndex  int
+}
+
+func main() {
+	var opts xerrs.StackOptions
+	flag.BoolVar(&opts.IgnoreLines, "ignore-lines", false, "compare stacks without line numbers")
+	flag.BoolVar(&opts.IgnoreVendor, "ignore-vendor", false, "leave vendored and module cache frames out of stacks")
+	maxStack := flag.Int("stack", 10, "number of stack locations printed per group")
+	flag.Parse()
+
+	if err := run(flag.Args(), opts, *maxStack, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "xerrs-dedupe:", err)
+		os.Exit(1)
+	}
+}
+
+func run(paths []string, opts xerrs.StackOptions, maxStack int, stdin io.Reader, stdout io.Writer) error {
+	clusters := make(map[string]*cluster)
+	var order []*cluster
+	index := 0
+
+	add := func(r io.Reader) error {
+		lines := bufio.NewScanner(r)
+		lines.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+		for lines.Scan() {
+			line := lines.Text()
+			i := strings.IndexByte(line, '{')
+			if i < 0 {
+				continue
+			}
+
+			var record xerrs.Record
+			if err := json.Unmarshal([]byte(line[i:]), &record); err != nil {
+				continue
+			}
+
+			seen := record.Time
+			if seen == nil {
+				if t, ok := prefixTime(line[:i]); ok {
+					seen = &t
+				}
+			}
+			index++
+
+			key := clusterKey(record, opts)
+			c, ok := clusters[key]
+			if !ok {
+				c = &cluster{record: record, firstIndex: index}
+				clusters[key] = c
+				order = append(order, c)
+			}
+			c.add(seen, index)
+		}
+
+		return xerrs.Extend(lines.Err())
+	}
+
+	if len(paths) == 0 {
+		if err := add(stdin); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return xerrs.Extend(err)
+		}
+
+		err = add(f)
+		f.Close()
+		if err != nil {
+			return xerrs.Wrapf(err, "read %s", path)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].count > order[j].count
+	})
+
+	out := bufio.NewWriter(stdout)
+	for _, c := range order {
+		fmt.Fprintf(out, "== %d occurrence(s), first seen %s, last seen %s\n", c.count, formatSeen(c.first, c.firstIndex), formatSeen(c.last, c.lastIndex))
+		fmt.Fprintf(out, "%s\n\n", strings.TrimPrefix(c.record.Details(maxStack), "\n"))
+	}
+
+	return xerrs.Extend(out.Flush())
+}
+
+// clusterKey returns the key of the cluster of record: its stack with the
+// Errorf format string, or with the normalized cause and root cause type
+func clusterKey(record xerrs.Record, opts xerrs.StackOptions) string {
+	message := xerrs.NormalizeMessage(record.Template)
+	if record.Template == "" {
+		message = xerrs.NormalizeMessage(record.Cause) + "\n" + record.Type
+	}
+
+	return xerrs.StackKey(record.Stack, opts) + "\n" + message
+}
+
+func (c *cluster) add(seen *time.Time, index int) {
+	c.count++
+	c.lastIndex = index
+
+	if seen == nil {
+		return
+	}
+	if c.first == nil || seen.Before(*c.first) {
+		c.first = seen
+	}
+	if c.last == nil || seen.After(*c.last) {
+		c.last = seen
+	}
+}
+
+// prefixLayouts are the timestamp formats recognized at the start of a log prefix
+var prefixLayouts = []string{time.RFC3339Nano, "2006/01/02 15:04:05", "2006-01-02 15:04:05"}
+
+// prefixTime returns the timestamp at the start of a log prefix
+func prefixTime(prefix string) (time.Time, bool) {
+	fields := strings.Fields(prefix)
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+
+	candidates := []string{strings.Trim(fields[0], "[]")}
+	if len(fields) > 1 {
+		candidates = append(candidates, strings.Trim(fields[0]+" "+fields[1], "[]"))
+	}
+
+	for _, candidate := range candidates {
+		for _, layout := range prefixLayouts {
+			if t, err := time.Parse(layout, candidate); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// formatSeen returns t, or the position of the error in the input when its
+// time is unknown
+func formatSeen(t *time.Time, index int) string {
+	if t == nil {
+		return fmt.Sprintf("#%d", index)
+	}
+
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+func TestRun(t *testing.T) {
+	in := strings.Join([]string{
+		`{"cause":"timeout","time":"2024-01-02T10:00:00Z","stack":[{"function":"main.a","file":"/app/a.go","line":1}]}`,
+		`2024/01/02 app: {"cause":"refused","stack":[{"function":"main.b","file":"/app/b.go","line":2}]}`,
+		`{"cause":"timeout","time":"2024-01-02T09:00:00Z","stack":[{"function":"main.a","file":"/app/a.go","line":5}]}`,
+		`not json`,
+	}, "\n")
+
+	cases := []struct {
+		name string
+		opts xerrs.StackOptions
+		want string
+	}{
+		{
+			name: "exact lines",
+			want: "== 1 occurrence(s), first seen 2024-01-02T10:00:00Z, last seen 2024-01-02T10:00:00Z\n" +
+				"[ERROR] timeout\n[TIME] 2024-01-02T10:00:00Z\n[GOROUTINE] 0\n[STACK]:\nmain.a [/app/a.go:1]\n\n" +
+				"== 1 occurrence(s), first seen #2, last seen #2\n" +
+				"[ERROR] refused\n[STACK]:\nmain.b [/app/b.go:2]\n\n" +
+				"== 1 occurrence(s), first seen 2024-01-02T09:00:00Z, last seen 2024-01-02T09:00:00Z\n" +
+				"[ERROR] timeout\n[TIME] 2024-01-02T09:00:00Z\n[GOROUTINE] 0\n[STACK]:\nmain.a [/app/a.go:5]\n\n",
+		},
+		{
+			name: "ignore lines",
+			opts: xerrs.StackOptions{IgnoreLines: true},
+			want: "== 2 occurrence(s), first seen 2024-01-02T09:00:00Z, last seen 2024-01-02T10:00:00Z\n" +
+				"[ERROR] timeout\n[TIME] 2024-01-02T10:00:00Z\n[GOROUTINE] 0\n[STACK]:\nmain.a [/app/a.go:1]\n\n" +
+				"== 1 occurrence(s), first seen #2, last seen #2\n" +
+				"[ERROR] refused\n[STACK]:\nmain.b [/app/b.go:2]\n\n",
+		},
+	}
+
+	for _, c := range cases {
+		var out bytes.Buffer
+		if err := run(nil, c.opts, 10, strings.NewReader(in), &out); err != nil {
+			t.Fatalf("unexpected error for %s: %v", c.name, err)
+		}
+
+		if out.String() != c.want {
+			t.Errorf("wrong output for %s: want=%v got=%v", c.name, c.want, out.String())
+		}
+	}
+}
+
+func TestRunGroupsMessages(t *testing.T) {
+	in := strings.Join([]string{
+		`2024-01-02T08:00:00Z ERROR {"cause":"shipment 7 not found","template":"shipment %d not found","stack":[{"function":"main.a","file":"/app/a.go","line":1}]}`,
+		`2024/01/02 09:30:00 {"cause":"shipment 8 not found","template":"shipment %d not found","stack":[{"function":"main.a","file":"/app/a.go","line":1}]}`,
+		`{"cause":"order 12 rejected","type":"*errors.errorString","stack":[{"function":"main.b","file":"/app/b.go","line":2}]}`,
+		`{"cause":"order 13 rejected","type":"*errors.errorString","stack":[{"function":"main.b","file":"/app/b.go","line":2}]}`,
+		`{"cause":"order 14 rejected","type":"*pq.Error","stack":[{"function":"main.b","file":"/app/b.go","line":2}]}`,
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := run(nil, xerrs.StackOptions{}, 0, strings.NewReader(in), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "== 2 occurrence(s), first seen 2024-01-02T08:00:00Z, last seen 2024-01-02T09:30:00Z\n" +
+		"[ERROR] shipment 7 not found\n[STACK]:\n\n" +
+		"== 2 occurrence(s), first seen #3, last seen #4\n" +
+		"[ERROR] order 12 rejected\n[STACK]:\n\n" +
+		"== 1 occurrence(s), first seen #5, last seen #5\n" +
+		"[ERROR] order 14 rejected\n[STACK]:\n\n"
+	if out.String() != want {
+		t.Errorf("wrong output: want=%v got=%v", want, out.String())
+	}
+}
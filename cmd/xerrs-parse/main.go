@@ -0,0 +1,63 @@
+// Command xerrs-parse converts logs containing xerrs.Details output to JSON
+// lines, one xerrs.Record per error. Other log lines are skipped.
+//
+// It reads the files given as arguments, or stdin:
+//
+//	xerrs-parse app.log > errors.jsonl
+//	kubectl logs app | xerrs-parse
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "xerrs-parse:", err)
+		os.Exit(1)
+	}
+}
+
+func run(paths []string, stdin io.Reader, stdout io.Writer) error {
+	out := bufio.NewWriter(stdout)
+	encoder := json.NewEncoder(out)
+
+	if len(paths) == 0 {
+		if err := convert(stdin, encoder); err != nil {
+			return err
+		}
+		return xerrs.Extend(out.Flush())
+	}
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return xerrs.Extend(err)
+		}
+
+		err = convert(f, encoder)
+		f.Close()
+		if err != nil {
+			return xerrs.Wrapf(err, "read %s", path)
+		}
+	}
+
+	return xerrs.Extend(out.Flush())
+}
+
+func convert(r io.Reader, encoder *json.Encoder) error {
+	scanner := xerrs.NewDetailsScanner(r)
+	for scanner.Scan() {
+		if err := encoder.Encode(scanner.Record()); err != nil {
+			return xerrs.Extend(err)
+		}
+	}
+
+	return xerrs.Extend(scanner.Err())
+}
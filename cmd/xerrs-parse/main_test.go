@@ -0,0 +1,21 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	in := strings.NewReader("boot\n[ERROR] failed\n[STACK]:\nmain.main [/app/main.go:3]\nbye\n")
+
+	var out bytes.Buffer
+	if err := run(nil, in, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{"cause":"failed","stack":[{"function":"main.main","file":"/app/main.go","line":3}]}` + "\n"
+	if out.String() != want {
+		t.Errorf("wrong output: want=%v got=%v", want, out.String())
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+var goTemplate = template.Must(template.New("go").Parse(`// Code generated by xerrsgen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .HasMask}}
+	"errors"
+{{- end}}
+{{- range .Imports}}
+	{{printf "%q" .}}
+{{- end}}
+
+	"github.com/RoseRocket/xerrs"
+)
+
+var (
+{{- range .Errors}}
+	// Err{{.Name}} - {{.Doc}}
+	Err{{.Name}} = xerrs.Define(xerrs.Definition{
+		Code:    {{printf "%q" .Code}},
+		Message: {{printf "%q" .Message}},
+		{{- if .HTTPStatus}}
+		HTTPStatus: {{.HTTPStatus}},
+		{{- end}}
+		{{- if .GRPCValue}}
+		GRPCCode: {{.GRPCValue}}, // codes.{{.GRPCName}}
+		{{- end}}
+		{{- if .SeverityConst}}
+		Severity: xerrs.{{.SeverityConst}},
+		{{- end}}
+		{{- if .Mask}}
+		Mask: errors.New({{printf "%q" .Mask}}),
+		{{- end}}
+		{{- if .Description}}
+		Description: {{printf "%q" .Description}},
+		{{- end}}
+	})
+{{end -}}
+)
+{{range .Errors}}
+// New{{.Name}} - creates a new Err{{.Name}} error
+func New{{.Name}}({{.Signature}}) error {
+	xerrs.Helper()
+	return Err{{.Name}}.New({{.Args}})
+}
+
+// Wrap{{.Name}} - creates a new Err{{.Name}} error which annotates err
+// If err is nil then nil is returned
+func Wrap{{.Name}}(err error{{if .Signature}}, {{.Signature}}{{end}}) error {
+	xerrs.Helper()
+	return Err{{.Name}}.Wrap(err{{if .Args}}, {{.Args}}{{end}})
+}
+{{end}}`))
+
+var markdownTemplate = template.Must(template.New("markdown").Parse(`# Errors
+
+Generated by xerrsgen from {{.Source}}.
+
+| Code | Name | Message | HTTP | gRPC | Severity | Mask | Description |
+| ---- | ---- | ------- | ---- | ---- | -------- | ---- | ----------- |
+{{- range .Errors}}
+| ` + "`{{.Code}}`" + ` | ` + "`Err{{.Name}}`" + ` | {{.MarkdownMessage}} | {{if .HTTPStatus}}{{.HTTPStatus}}{{end}} | {{.GRPCName}} | {{.SeverityName}} | {{.MarkdownMask}} | {{.MarkdownDescription}} |
+{{- end}}
+`))
+
+type templateData struct {
+	Source  string
+	Package string
+	Imports []string
+	HasMask bool
+	Errors  []templateError
+}
+
+type templateError struct {
+	ErrorSpec
+	Doc           string
+	GRPCValue     int
+	GRPCName      string
+	SeverityConst string
+	SeverityName  string
+	Signature     string
+	Args          string
+}
+
+// MarkdownMessage returns the message with its typed params for the reference table
+func (e templateError) MarkdownMessage() string {
+	message := "`" + escapeMarkdown(e.Message) + "`"
+	if len(e.Params) == 0 {
+		return message
+	}
+
+	return message + " (`" + e.Signature + "`)"
+}
+
+// MarkdownMask returns the escaped mask for the reference table
+func (e templateError) MarkdownMask() string {
+	return escapeMarkdown(e.Mask)
+}
+
+// MarkdownDescription returns the escaped description for the reference table
+func (e templateError) MarkdownDescription() string {
+	return escapeMarkdown(e.Description)
+}
+
+func newTemplateData(spec *Spec, source string) (*templateData, error) {
+	data := &templateData{
+		Source:  source,
+		Package: spec.Package,
+		Imports: spec.Imports,
+	}
+
+	for _, e := range spec.Errors {
+		grpcCode, err := e.grpcCode()
+		if err != nil {
+			return nil, err
+		}
+
+		severity, err := e.severity()
+		if err != nil {
+			return nil, err
+		}
+
+		params := make([]string, len(e.Params))
+		args := make([]string, len(e.Params))
+		for i, p := range e.Params {
+			params[i] = p.Name + " " + p.Type
+			args[i] = p.Name
+		}
+
+		doc := strings.Join(strings.Fields(e.Description), " ")
+		if doc == "" {
+			doc = e.Message
+		}
+
+		te := templateError{
+			ErrorSpec:    e,
+			Doc:          doc,
+			GRPCValue:    grpcCode,
+			GRPCName:     grpcName(grpcCode, e.GRPCCode),
+			SeverityName: severity.String(),
+			Signature:    strings.Join(params, ", "),
+			Args:         strings.Join(args, ", "),
+		}
+		if severity != xerrs.SeverityUnset {
+			name := severity.String()
+			te.SeverityConst = "Severity" + strings.ToUpper(name[:1]) + name[1:]
+		}
+
+		data.HasMask = data.HasMask || e.Mask != ""
+		data.Errors = append(data.Errors, te)
+	}
+
+	return data, nil
+}
+
+// generateGo returns the formatted Go source of the catalog
+func generateGo(spec *Spec, source string) ([]byte, error) {
+	data, err := newTemplateData(spec, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := goTemplate.Execute(&buf, data); err != nil {
+		return nil, xerrs.Extend(err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, xerrs.Wrapf(err, "format generated code:\n%s", buf.String())
+	}
+
+	return formatted, nil
+}
+
+// generateMarkdown returns the Markdown reference table of the catalog
+func generateMarkdown(spec *Spec, source string) ([]byte, error) {
+	data, err := newTemplateData(spec, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := markdownTemplate.Execute(&buf, data); err != nil {
+		return nil, xerrs.Extend(err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// grpcName returns the name of a gRPC code for comments and documentation
+func grpcName(code int, given string) string {
+	if given == "" {
+		return ""
+	}
+
+	for name, value := range grpcCodes {
+		if value == code {
+			return name
+		}
+	}
+
+	return strconv.Itoa(code)
+}
+
+func escapeMarkdown(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
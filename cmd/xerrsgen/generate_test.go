@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RoseRocket/xerrs/xerrstest"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, name := range []string{"shipments.yaml", "carriers.json"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			spec, err := parseSpec(name, data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			code, err := generateGo(spec, name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			xerrstest.AssertGolden(t, filepath.Join("testdata", name+".go.golden"), code)
+
+			doc, err := generateMarkdown(spec, name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			xerrstest.AssertGolden(t, filepath.Join("testdata", name+".md.golden"), doc)
+		})
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		spec        string
+		wantErr     string
+	}{
+		{
+			description: "unknown field",
+			spec:        "errors:\n  - name: A\n    code: a\n    message: a\n    status: 1\n",
+			wantErr:     "field status not found",
+		},
+		{
+			description: "missing code",
+			spec:        "errors:\n  - name: A\n    message: a\n",
+			wantErr:     "A: code is required",
+		},
+		{
+			description: "duplicate code",
+			spec:        "errors:\n  - {name: A, code: a, message: a}\n  - {name: B, code: a, message: b}\n",
+			wantErr:     `B: duplicate code "a"`,
+		},
+		{
+			description: "unexported name",
+			spec:        "errors:\n  - {name: a, code: a, message: a}\n",
+			wantErr:     `name "a" is not an exported identifier`,
+		},
+		{
+			description: "params do not match verbs",
+			spec:        "errors:\n  - {name: A, code: a, message: 'a %d %s', params: [{name: id, type: int}]}\n",
+			wantErr:     "A: message has 2 verbs but 1 params",
+		},
+		{
+			description: "invalid param type",
+			spec:        "errors:\n  - {name: A, code: a, message: 'a %d', params: [{name: id, type: 'map['}]}\n",
+			wantErr:     `A: param id has invalid type "map["`,
+		},
+		{
+			description: "unknown grpc code",
+			spec:        "errors:\n  - {name: A, code: a, message: a, grpc: Missing}\n",
+			wantErr:     `A: unknown gRPC code "Missing"`,
+		},
+		{
+			description: "unknown severity",
+			spec:        "errors:\n  - {name: A, code: a, message: a, severity: fatal}\n",
+			wantErr:     `A: unknown severity "fatal"`,
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := parseSpec("errors.yaml", []byte(test.spec))
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("wrong error: want=%v got=%v", test.wantErr, err)
+			}
+		})
+	}
+}
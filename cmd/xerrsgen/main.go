@@ -0,0 +1,95 @@
+// Command xerrsgen generates xerrs error catalogs from a YAML or JSON spec.
+//
+// It writes Go constructors built on xerrs.Define, e.g.
+// NewShipmentNotFound(id int64) error, and optionally a Markdown reference
+// table of every error. Use it with go:generate:
+//
+//	//go:generate go run github.com/RoseRocket/xerrs/cmd/xerrsgen -spec errors.yaml -doc ERRORS.md
+//
+// Spec format:
+//
+//	package: shipments
+//	imports:
+//	  - time
+//	errors:
+//	  - name: ShipmentNotFound
+//	    code: shipment_not_found
+//	    message: shipment %d not found
+//	    params:
+//	      - name: id
+//	        type: int64
+//	    mask: Shipment not found
+//	    http: 404
+//	    grpc: NotFound
+//	    severity: info
+//	    description: The shipment does not exist or was deleted.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+func main() {
+	specPath := flag.String("spec", "errors.yaml", "YAML or JSON spec of the error catalog")
+	outPath := flag.String("out", "", "generated Go file (default <spec name>_gen.go)")
+	docPath := flag.String("doc", "", "generated Markdown reference (default none)")
+	pkg := flag.String("package", "", "package name (default the spec package or $GOPACKAGE)")
+	flag.Parse()
+
+	if err := run(*specPath, *outPath, *docPath, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "xerrsgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, outPath, docPath, pkg string) error {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return xerrs.Extend(err)
+	}
+
+	spec, err := parseSpec(specPath, data)
+	if err != nil {
+		return err
+	}
+
+	if pkg != "" {
+		spec.Package = pkg
+	}
+	if spec.Package == "" {
+		spec.Package = os.Getenv("GOPACKAGE")
+	}
+	if spec.Package == "" {
+		return xerrs.New("package name is required, set it in the spec or with -package")
+	}
+
+	source := filepath.Base(specPath)
+	if outPath == "" {
+		outPath = strings.TrimSuffix(specPath, filepath.Ext(specPath)) + "_gen.go"
+	}
+
+	code, err := generateGo(spec, source)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, code, 0644); err != nil {
+		return xerrs.Extend(err)
+	}
+
+	if docPath == "" {
+		return nil
+	}
+
+	doc, err := generateMarkdown(spec, source)
+	if err != nil {
+		return err
+	}
+
+	return xerrs.Extend(os.WriteFile(docPath, doc, 0644))
+}
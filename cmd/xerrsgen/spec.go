@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/RoseRocket/xerrs"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec - the YAML or JSON description of an error catalog
+type Spec struct {
+	Package string `json:"package" yaml:"package"`
+	// Imports are the import paths of packages used by param types, e.g. "time"
+	Imports []string    `json:"imports" yaml:"imports"`
+	Errors  []ErrorSpec `json:"errors" yaml:"errors"`
+}
+
+// ErrorSpec - one error of the catalog
+type ErrorSpec struct {
+	// Name is the Go name, ErrName and NewName are generated from it
+	Name        string      `json:"name" yaml:"name"`
+	Code        string      `json:"code" yaml:"code"`
+	Message     string      `json:"message" yaml:"message"`
+	Params      []ParamSpec `json:"params" yaml:"params"`
+	Mask        string      `json:"mask" yaml:"mask"`
+	HTTPStatus  int         `json:"http" yaml:"http"`
+	GRPCCode    string      `json:"grpc" yaml:"grpc"`
+	Severity    string      `json:"severity" yaml:"severity"`
+	Description string      `json:"description" yaml:"description"`
+}
+
+// ParamSpec - a typed parameter of the error message
+type ParamSpec struct {
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type" yaml:"type"`
+}
+
+// grpcCodes maps google.golang.org/grpc/codes names to their values
+var grpcCodes = map[string]int{
+	"OK":                 0,
+	"Canceled":           1,
+	"Unknown":            2,
+	"InvalidArgument":    3,
+	"DeadlineExceeded":   4,
+	"NotFound":           5,
+	"AlreadyExists":      6,
+	"PermissionDenied":   7,
+	"ResourceExhausted":  8,
+	"FailedPrecondition": 9,
+	"Aborted":            10,
+	"OutOfRange":         11,
+	"Unimplemented":      12,
+	"Internal":           13,
+	"Unavailable":        14,
+	"DataLoss":           15,
+	"Unauthenticated":    16,
+}
+
+// parseSpec decodes data as JSON if name has a .json extension, and as YAML otherwise
+func parseSpec(name string, data []byte) (*Spec, error) {
+	var spec Spec
+
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&spec); err != nil {
+			return nil, xerrs.Wrapf(err, "decode %s", name)
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&spec); err != nil {
+			return nil, xerrs.Wrapf(err, "decode %s", name)
+		}
+	}
+
+	if err := spec.validate(); err != nil {
+		return nil, xerrs.Wrapf(err, "invalid spec %s", name)
+	}
+
+	return &spec, nil
+}
+
+func (spec *Spec) validate() error {
+	if spec.Package != "" && !token.IsIdentifier(spec.Package) {
+		return xerrs.Errorf("package %q is not a valid identifier", spec.Package)
+	}
+
+	for _, path := range spec.Imports {
+		if path == "" || strings.ContainsAny(path, "\" \t\n") {
+			return xerrs.Errorf("invalid import path %q", path)
+		}
+	}
+
+	names := make(map[string]bool)
+	codes := make(map[string]bool)
+	for i, e := range spec.Errors {
+		if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+			return xerrs.Errorf("errors[%d]: name %q is not an exported identifier", i, e.Name)
+		}
+		if names[e.Name] {
+			return xerrs.Errorf("errors[%d]: duplicate name %q", i, e.Name)
+		}
+		names[e.Name] = true
+
+		if e.Code == "" {
+			return xerrs.Errorf("%s: code is required", e.Name)
+		}
+		if codes[e.Code] {
+			return xerrs.Errorf("%s: duplicate code %q", e.Name, e.Code)
+		}
+		codes[e.Code] = true
+
+		if e.Message == "" {
+			return xerrs.Errorf("%s: message is required", e.Name)
+		}
+		if verbs := countVerbs(e.Message); verbs != len(e.Params) {
+			return xerrs.Errorf("%s: message has %d verbs but %d params", e.Name, verbs, len(e.Params))
+		}
+
+		for _, p := range e.Params {
+			if !token.IsIdentifier(p.Name) || p.Name == "err" {
+				return xerrs.Errorf("%s: param name %q is not a valid identifier", e.Name, p.Name)
+			}
+			if _, err := parser.ParseExpr(p.Type); err != nil || p.Type == "" {
+				return xerrs.Errorf("%s: param %s has invalid type %q", e.Name, p.Name, p.Type)
+			}
+		}
+
+		if _, err := e.grpcCode(); err != nil {
+			return xerrs.Wrap(err, e.Name)
+		}
+		if _, err := e.severity(); err != nil {
+			return xerrs.Wrap(err, e.Name)
+		}
+	}
+
+	return nil
+}
+
+// grpcCode returns the numeric gRPC code given by name or number
+func (e ErrorSpec) grpcCode() (int, error) {
+	if e.GRPCCode == "" {
+		return 0, nil
+	}
+
+	if code, ok := grpcCodes[e.GRPCCode]; ok {
+		return code, nil
+	}
+
+	code, err := strconv.Atoi(e.GRPCCode)
+	if err != nil {
+		return 0, xerrs.Errorf("unknown gRPC code %q", e.GRPCCode)
+	}
+
+	return code, nil
+}
+
+func (e ErrorSpec) severity() (xerrs.Severity, error) {
+	if e.Severity == "" {
+		return xerrs.SeverityUnset, nil
+	}
+
+	return xerrs.ParseSeverity(e.Severity)
+}
+
+// countVerbs returns the number of formatting verbs in format, ignoring %%
+func countVerbs(format string) int {
+	count := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '%' {
+			i++
+			continue
+		}
+		count++
+	}
+
+	return count
+}
@@ -0,0 +1,19 @@
+// Command xerrslint reports misuse of the xerrs package, see package xerrslint.
+//
+// Run it on its own:
+//
+//	xerrslint ./...
+//
+// or through go vet:
+//
+//	go vet -vettool=$(which xerrslint) ./...
+package main
+
+import (
+	"github.com/RoseRocket/xerrs/xerrslint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(xerrslint.Analyzer)
+}
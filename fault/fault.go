@@ -0,0 +1,343 @@
+// Package fault injects errors at named points of the code, so tests can
+// exercise error paths without mocking every dependency.
+//
+// Points are armed with Arm, with WithFault for a single context, or with the
+// XERRS_FAULTS environment variable, see Parse and EnvErr. While nothing is
+// armed Check only loads an atomic flag.
+package fault
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+const (
+	// InjectedKey - xerrs data key which marks an error as injected by Check
+	InjectedKey = "fault.injected"
+
+	// PointKey - xerrs data key which holds the name of the point an error was injected at
+	PointKey = "fault.point"
+
+	// EnvVar - environment variable read when the package is initialized, see Parse and EnvErr
+	EnvVar = "XERRS_FAULTS"
+)
+
+// Fault - describes the errors returned by Check at an armed point.
+// Zero values fall back to the defaults documented on each field.
+type Fault struct {
+	// Probability of returning an error from a call to Check, within (0, 1].
+	// Defaults to 1.
+	Probability float64
+	// Count limits the number of errors returned. Zero means no limit.
+	Count int
+	// Code is the code of the errors, see xerrs.WithCode.
+	Code string
+	// Mask is the mask of the errors, see xerrs.Mask.
+	Mask error
+	// Err is wrapped by the errors, e.g. to simulate sql.ErrNoRows.
+	Err error
+	// Message is the message of the errors. Defaults to "fault injected at <name>".
+	Message string
+}
+
+// point is an armed Fault with the number of errors it returned
+type point struct {
+	fault Fault
+	fired atomic.Int64
+}
+
+// contextFaults are the points armed in a context by WithFault. They are
+// ignored once Reset moves on to a new generation.
+type contextFaults struct {
+	generation uint64
+	points     map[string]*point
+}
+
+var (
+	// armed is set while a point is armed, so Check returns quickly otherwise
+	armed atomic.Bool
+
+	mu     sync.RWMutex
+	points = map[string]*point{}
+	// contexts is set once WithFault was called in the current generation
+	contexts   bool
+	generation uint64
+
+	envErr error
+
+	random = rand.Float64
+)
+
+type contextKey struct{}
+
+func init() {
+	envErr = LoadEnv()
+}
+
+// EnvErr - returns the error of reading the XERRS_FAULTS environment variable
+// when the package was initialized. Points of an invalid spec are not armed.
+func EnvErr() error {
+	return envErr
+}
+
+// LoadEnv - arms the points of the spec in the XERRS_FAULTS environment
+// variable, see Parse. It is called when the package is initialized.
+// If the spec is invalid then no point is armed.
+func LoadEnv() error {
+	spec := os.Getenv(EnvVar)
+	if spec == "" {
+		return nil
+	}
+
+	faults, err := Parse(spec)
+	if err != nil {
+		return xerrs.Wrapf(err, "fault: invalid %s", EnvVar)
+	}
+
+	for name, f := range faults {
+		Arm(name, f)
+	}
+
+	return nil
+}
+
+// Arm - makes Check return errors described by f at the point name.
+// A point which was armed before is replaced.
+func Arm(name string, f Fault) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	points[name] = &point{fault: f}
+	armed.Store(true)
+}
+
+// Disarm - makes Check at the point name return nil again, unless it is armed
+// in the context
+func Disarm(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(points, name)
+	armed.Store(len(points) > 0 || contexts)
+}
+
+// Reset - disarms every point armed with Arm, WithFault or the environment
+// variable. Contexts returned by WithFault before have no faults afterwards.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	points = map[string]*point{}
+	contexts = false
+	generation++
+	armed.Store(false)
+}
+
+// Fired - returns the number of errors returned at the point name since it was armed
+func Fired(name string) int {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if p, ok := points[name]; ok {
+		return int(p.fired.Load())
+	}
+
+	return 0
+}
+
+// WithFault - returns a context in which Check returns errors described by f
+// at the point name, in addition to the points armed with Arm. Faults of the
+// context take precedence and are shared by the contexts derived from it.
+// They stay armed until Reset.
+func WithFault(ctx context.Context, name string, f Fault) context.Context {
+	mu.Lock()
+	defer mu.Unlock()
+
+	faults := contextFaults{generation: generation, points: map[string]*point{}}
+	if parent, ok := ctx.Value(contextKey{}).(contextFaults); ok && parent.generation == generation {
+		for n, p := range parent.points {
+			faults.points[n] = p
+		}
+	}
+	faults.points[name] = &point{fault: f}
+
+	contexts = true
+	armed.Store(true)
+
+	return context.WithValue(ctx, contextKey{}, faults)
+}
+
+// Check - returns an error if the point name is armed in ctx or with Arm and
+// the fault fires, otherwise nil.
+// The error is marked with InjectedKey and PointKey, so logs show it was synthetic.
+func Check(ctx context.Context, name string) error {
+	if !armed.Load() {
+		return nil
+	}
+
+	p := lookup(ctx, name)
+	if p == nil || !p.fire() {
+		return nil
+	}
+
+	return p.fault.err(name)
+}
+
+// IsInjected - returns true if err or one of its xerr causes was returned by Check
+func IsInjected(err error) bool {
+	v, ok := xerrs.GetData(err, InjectedKey)
+	if !ok {
+		return false
+	}
+
+	injected, _ := v.(bool)
+	return injected
+}
+
+func lookup(ctx context.Context, name string) *point {
+	var faults contextFaults
+	if ctx != nil {
+		faults, _ = ctx.Value(contextKey{}).(contextFaults)
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if faults.generation == generation {
+		if p, ok := faults.points[name]; ok {
+			return p
+		}
+	}
+
+	return points[name]
+}
+
+// fire reports whether the point returns an error from this call
+func (p *point) fire() bool {
+	if p.fault.Probability > 0 && p.fault.Probability < 1 && random() >= p.fault.Probability {
+		return false
+	}
+
+	if p.fault.Count <= 0 {
+		p.fired.Add(1)
+		return true
+	}
+
+	for {
+		fired := p.fired.Load()
+		if fired >= int64(p.fault.Count) {
+			return false
+		}
+		if p.fired.CompareAndSwap(fired, fired+1) {
+			return true
+		}
+	}
+}
+
+// err creates the error returned by Check with the stack of its caller
+func (f Fault) err(name string) error {
+	message := f.Message
+	if message == "" {
+		message = "fault injected at " + name
+	}
+
+	opts := []xerrs.Option{
+		xerrs.WithData(InjectedKey, true),
+		xerrs.WithData(PointKey, name),
+	}
+	if f.Code != "" {
+		opts = append(opts, xerrs.WithCode(f.Code))
+	}
+	if f.Mask != nil {
+		opts = append(opts, xerrs.WithMask(f.Mask))
+	}
+
+	if f.Err != nil {
+		return xerrs.WrapSkip(2, f.Err, message, opts...)
+	}
+
+	return xerrs.NewSkip(2, message, opts...)
+}
+
+// Parse - parses a spec of faults, as read from the XERRS_FAULTS environment variable.
+// Points are separated by ";" and each is a name followed by optional
+// "=key:value" pairs separated by ",":
+//
+//	db.insert;cache.get=p:0.1;queue.push=count:3,code:queue_down,mask:service unavailable,msg:queue is full
+//
+// Keys are p (Probability), count (Count), code (Code), mask (Mask) and msg (Message).
+// Mask and message can not contain "," or ";".
+func Parse(spec string) (map[string]Fault, error) {
+	faults := map[string]Fault{}
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, params, _ := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, xerrs.Errorf("fault: missing point name in %q", entry)
+		}
+
+		f, err := parseFault(params)
+		if err != nil {
+			return nil, xerrs.Wrapf(err, "fault: invalid point %q", name)
+		}
+		faults[name] = f
+	}
+
+	return faults, nil
+}
+
+func parseFault(params string) (Fault, error) {
+	var f Fault
+
+	for _, param := range strings.Split(params, ",") {
+		if strings.TrimSpace(param) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(param, ":")
+		if !ok {
+			return Fault{}, xerrs.Errorf("missing value of %q", param)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "p":
+			p, err := strconv.ParseFloat(value, 64)
+			if err != nil || p <= 0 || p > 1 {
+				return Fault{}, xerrs.Errorf("probability must be within (0, 1]: %q", value)
+			}
+			f.Probability = p
+		case "count":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return Fault{}, xerrs.Errorf("count must be a non negative integer: %q", value)
+			}
+			f.Count = n
+		case "code":
+			f.Code = value
+		case "mask":
+			f.Mask = errors.New(value)
+		case "msg":
+			f.Message = value
+		default:
+			return Fault{}, xerrs.Errorf("unknown key %q", key)
+		}
+	}
+
+	return f, nil
+}
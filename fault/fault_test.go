@@ -0,0 +1,251 @@
+package fault
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+func TestCheckDisarmed(t *testing.T) {
+	defer Reset()
+
+	if err := Check(context.Background(), "db.insert"); err != nil {
+		t.Errorf("expected nil error, got=%v", err)
+	}
+
+	Arm("db.insert", Fault{})
+	if err := Check(context.Background(), "db.update"); err != nil {
+		t.Errorf("expected nil error for other point, got=%v", err)
+	}
+
+	Disarm("db.insert")
+	if err := Check(context.Background(), "db.insert"); err != nil {
+		t.Errorf("expected nil error after Disarm, got=%v", err)
+	}
+	if armed.Load() {
+		t.Errorf("expected fast path after the last point was disarmed")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer Reset()
+
+	mask := errors.New("database unavailable")
+	Arm("db.insert", Fault{Code: "db_down", Mask: mask})
+
+	err := Check(context.Background(), "db.insert")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if err.Error() != mask.Error() {
+		t.Errorf("wrong message: want=%v got=%v", mask.Error(), err.Error())
+	}
+	if got := xerrs.Internal(err); got != "fault injected at db.insert" {
+		t.Errorf("wrong internal message: want=%v got=%v", "fault injected at db.insert", got)
+	}
+	if got := xerrs.Code(err); got != "db_down" {
+		t.Errorf("wrong code: want=%v got=%v", "db_down", got)
+	}
+	if !IsInjected(err) {
+		t.Errorf("expected error to be marked as injected")
+	}
+	if got, _ := xerrs.GetData(err, PointKey); got != "db.insert" {
+		t.Errorf("wrong point: want=%v got=%v", "db.insert", got)
+	}
+
+	stack := xerrs.Stack(err)
+	if len(stack) == 0 || !strings.HasSuffix(stack[0].Function, ".TestCheck") {
+		t.Errorf("wrong stack: want to start at TestCheck got=%v", stack)
+	}
+}
+
+func TestCheckWrapsErr(t *testing.T) {
+	defer Reset()
+
+	Arm("db.select", Fault{Err: sql.ErrNoRows, Message: "no shipment"})
+
+	err := Check(context.Background(), "db.select")
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected error to wrap sql.ErrNoRows, got=%v", err)
+	}
+	if got := xerrs.Internal(err); !strings.HasPrefix(got, "no shipment: ") {
+		t.Errorf("wrong internal message: want=%v got=%v", "no shipment: ...", got)
+	}
+	if !IsInjected(err) {
+		t.Errorf("expected error to be marked as injected")
+	}
+}
+
+func TestCheckCount(t *testing.T) {
+	defer Reset()
+
+	Arm("queue.push", Fault{Count: 2})
+
+	errs := 0
+	for i := 0; i < 5; i++ {
+		if Check(context.Background(), "queue.push") != nil {
+			errs++
+		}
+	}
+
+	if errs != 2 {
+		t.Errorf("wrong number of errors: want=%v got=%v", 2, errs)
+	}
+	if got := Fired("queue.push"); got != 2 {
+		t.Errorf("wrong fired count: want=%v got=%v", 2, got)
+	}
+}
+
+func TestCheckProbability(t *testing.T) {
+	defer Reset()
+	defer func(r func() float64) { random = r }(random)
+
+	values := []float64{0.1, 0.5, 0.29, 0.3, 0.9}
+	random = func() float64 {
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+
+	Arm("cache.get", Fault{Probability: 0.3})
+
+	var got []bool
+	for i := 0; i < 5; i++ {
+		got = append(got, Check(context.Background(), "cache.get") != nil)
+	}
+
+	want := []bool{true, false, true, false, false}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("wrong results: want=%v got=%v", want, got)
+			break
+		}
+	}
+}
+
+func TestWithFault(t *testing.T) {
+	defer Reset()
+
+	Arm("db.insert", Fault{Code: "global"})
+
+	ctx := WithFault(context.Background(), "db.insert", Fault{Code: "context", Count: 1})
+	ctx = WithFault(ctx, "cache.get", Fault{})
+
+	if got := xerrs.Code(Check(ctx, "db.insert")); got != "context" {
+		t.Errorf("wrong code: want=%v got=%v", "context", got)
+	}
+	if err := Check(ctx, "db.insert"); err != nil {
+		t.Errorf("expected nil error after count was reached, got=%v", err)
+	}
+	if err := Check(ctx, "cache.get"); err == nil {
+		t.Errorf("expected error for point armed in context")
+	}
+	if err := Check(context.Background(), "cache.get"); err != nil {
+		t.Errorf("expected nil error without context, got=%v", err)
+	}
+	if got := xerrs.Code(Check(context.Background(), "db.insert")); got != "global" {
+		t.Errorf("wrong code: want=%v got=%v", "global", got)
+	}
+
+	Disarm("db.insert")
+	if !armed.Load() {
+		t.Errorf("expected context faults to stay armed after Disarm")
+	}
+
+	Reset()
+	if armed.Load() {
+		t.Errorf("expected fast path after Reset")
+	}
+	Arm("queue.push", Fault{})
+	if err := Check(ctx, "cache.get"); err != nil {
+		t.Errorf("expected nil error for context created before Reset, got=%v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	faults, err := Parse("db.insert; cache.get=p:0.1 ;queue.push=count:3,code:queue_down,mask:service unavailable,msg:queue is full")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(faults) != 3 {
+		t.Fatalf("wrong number of faults: want=%v got=%v", 3, len(faults))
+	}
+
+	if f := faults["db.insert"]; f.Probability != 0 || f.Count != 0 || f.Code != "" || f.Mask != nil {
+		t.Errorf("wrong fault for db.insert: got=%+v", f)
+	}
+	if f := faults["cache.get"]; f.Probability != 0.1 {
+		t.Errorf("wrong probability: want=%v got=%v", 0.1, f.Probability)
+	}
+
+	f := faults["queue.push"]
+	if f.Count != 3 {
+		t.Errorf("wrong count: want=%v got=%v", 3, f.Count)
+	}
+	if f.Code != "queue_down" {
+		t.Errorf("wrong code: want=%v got=%v", "queue_down", f.Code)
+	}
+	if f.Mask == nil || f.Mask.Error() != "service unavailable" {
+		t.Errorf("wrong mask: want=%v got=%v", "service unavailable", f.Mask)
+	}
+	if f.Message != "queue is full" {
+		t.Errorf("wrong message: want=%v got=%v", "queue is full", f.Message)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"=p:0.5", "missing point name"},
+		{"db.insert=p", "missing value"},
+		{"db.insert=p:2", "probability must be within (0, 1]"},
+		{"db.insert=p:x", "probability must be within (0, 1]"},
+		{"db.insert=count:-1", "count must be a non negative integer"},
+		{"db.insert=delay:1s", "unknown key"},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.spec)
+		if err == nil {
+			t.Errorf("expected error for %q, got nil", test.spec)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("wrong error for %q: want=%v got=%v", test.spec, test.want, err)
+		}
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	defer Reset()
+
+	t.Setenv(EnvVar, "db.insert=code:db_down")
+	if err := LoadEnv(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := xerrs.Code(Check(context.Background(), "db.insert")); got != "db_down" {
+		t.Errorf("wrong code: want=%v got=%v", "db_down", got)
+	}
+
+	t.Setenv(EnvVar, "db.insert=p:2")
+	if err := LoadEnv(); err == nil || !strings.Contains(err.Error(), "fault: invalid "+EnvVar) {
+		t.Errorf("wrong error: want=%v got=%v", "fault: invalid "+EnvVar, err)
+	}
+}
+
+func BenchmarkCheckDisarmed(b *testing.B) {
+	Reset()
+	ctx := context.Background()
+
+	for i := 0; i < b.N; i++ {
+		Check(ctx, "db.insert")
+	}
+}
@@ -0,0 +1,151 @@
+package xerrs
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FingerprintOptions - controls which parts of an error contribute to its fingerprint
+type FingerprintOptions struct {
+	// Frames is the number of in-module stack frames used. Zero means the stack is ignored.
+	Frames int
+	// IgnoreLines leaves line numbers out, so fingerprints survive unrelated code changes.
+	IgnoreLines bool
+	// ModulePrefix selects in-module frames by function name prefix, e.g. "github.com/roserocket/".
+	// If empty then every frame outside the Go standard library is in-module.
+	ModulePrefix string
+}
+
+// DefaultFingerprintOptions - options used by Fingerprint
+var DefaultFingerprintOptions = FingerprintOptions{
+	Frames:      5,
+	IgnoreLines: true,
+}
+
+var fingerprintReplacements = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`), "<uuid>"},
+	{regexp.MustCompile(`(?i)0x[0-9a-f]+`), "<hex>"},
+	{regexp.MustCompile(`[0-9]+`), "<n>"},
+}
+
+// SetFingerprint - sets an explicit fingerprint which overrides the computed one
+// If err is not xerr then nothing happens
+func SetFingerprint(err error, fingerprint string) {
+	if x, ok := err.(*xerr); ok {
+		x.fingerprint = fingerprint
+	}
+}
+
+// Fingerprint - returns a stable hash which is equal for errors of the same kind,
+// using DefaultFingerprintOptions
+// If an explicit fingerprint was set with SetFingerprint then it is returned as is
+// If err is nil then "" is returned
+func Fingerprint(err error) string {
+	return FingerprintWith(err, DefaultFingerprintOptions)
+}
+
+// FingerprintWith - returns a stable hash computed from the root cause type,
+// its Errorf format string or normalized message and the top in-module frames
+// of the origin stack
+// If an explicit fingerprint was set with SetFingerprint then it is returned as is
+// If err is nil then "" is returned
+func FingerprintWith(err error, opts FingerprintOptions) string {
+	if err == nil {
+		return ""
+	}
+
+	var origin *xerr
+	root := err
+	for root != nil {
+		if x, ok := root.(*xerr); ok {
+			if x.fingerprint != "" {
+				return x.fingerprint
+			}
+			origin = x
+			root = x.cause
+			continue
+		}
+
+		next := errors.Unwrap(root)
+		if next == nil {
+			break
+		}
+		root = next
+	}
+
+	message := NormalizeMessage(root.Error())
+	if origin != nil && origin.msg == "" && origin.format != "" {
+		// Errorf keeps its format string which is already free of variable parts
+		message = origin.format
+	}
+
+	parts := []string{
+		fmt.Sprintf("%T", root),
+		message,
+	}
+
+	if origin != nil {
+		count := 0
+		for _, location := range origin.stack {
+			if count >= opts.Frames {
+				break
+			}
+			if !opts.inModule(location.Function) {
+				continue
+			}
+
+			frame := location.Function + " " + location.File
+			if !opts.IgnoreLines {
+				frame = fmt.Sprintf("%s:%d", frame, location.Line)
+			}
+			parts = append(parts, frame)
+			count++
+		}
+	}
+
+	sum := sha1.Sum([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeMessage - replaces the variable parts of an error message, such as
+// numbers, hex values and UUIDs, with placeholders
+func NormalizeMessage(message string) string {
+	for _, r := range fingerprintReplacements {
+		message = r.pattern.ReplaceAllString(message, r.replacement)
+	}
+
+	return message
+}
+
+// inModule reports whether function belongs to the code being fingerprinted
+func (opts FingerprintOptions) inModule(function string) bool {
+	if opts.ModulePrefix != "" {
+		return strings.HasPrefix(function, opts.ModulePrefix)
+	}
+
+	return !isStandardLibrary(function)
+}
+
+// isStandardLibrary reports whether a fully qualified function name belongs to
+// the Go standard library, whose import paths have no dot in the first element
+func isStandardLibrary(function string) bool {
+	first := function
+	if i := strings.Index(first, "/"); i >= 0 {
+		first = first[:i]
+	} else if i := strings.Index(first, "."); i >= 0 {
+		first = first[:i]
+	}
+
+	if first == "main" {
+		return false
+	}
+
+	return !strings.Contains(first, ".")
+}
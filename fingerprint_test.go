@@ -0,0 +1,84 @@
+package xerrs
+
+import (
+	"errors"
+	"testing"
+)
+
+func newShipmentError(id int) error {
+	return Errorf("shipment %d not found", id)
+}
+
+func newCarrierError(id int) error {
+	return Errorf("shipment %d not found", id)
+}
+
+func TestFingerprint(t *testing.T) {
+	if Fingerprint(nil) != "" {
+		t.Errorf("expected empty fingerprint for nil error")
+	}
+
+	a := Fingerprint(newShipmentError(123))
+	b := Fingerprint(Wrap(newShipmentError(456), "load"))
+	if a == "" || a != b {
+		t.Errorf("expected equal fingerprints: got=%v and %v", a, b)
+	}
+
+	if c := Fingerprint(newCarrierError(123)); c == a {
+		t.Errorf("expected different fingerprints for different stacks: got=%v", c)
+	}
+
+	if c := Fingerprint(errors.New("shipment 1 not found")); c == a {
+		t.Errorf("expected different fingerprints for plain error: got=%v", c)
+	}
+
+	noStack := FingerprintOptions{}
+	if FingerprintWith(newShipmentError(1), noStack) != FingerprintWith(newCarrierError(2), noStack) {
+		t.Errorf("expected equal fingerprints when the stack is ignored")
+	}
+
+	if FingerprintWith(Errorf("carrier %s failed", "ups"), noStack) != FingerprintWith(Errorf("carrier %s failed", "fedex"), noStack) {
+		t.Errorf("expected the Errorf format to be used")
+	}
+
+	uuids := []string{
+		Fingerprint(Extend(errors.New("order 3f2504e0-4f89-11d3-9a0c-0305e82c3301 failed"))),
+		Fingerprint(Extend(errors.New("order 6ba7b810-9dad-11d1-80b4-00c04fd430c8 failed"))),
+	}
+	if uuids[0] != uuids[1] {
+		t.Errorf("expected uuids to be stripped: got=%v", uuids)
+	}
+
+	err := Wrap(newShipmentError(1), "load")
+	SetFingerprint(Cause(err), "shipment-not-found")
+	if got := Fingerprint(err); got != "shipment-not-found" {
+		t.Errorf("wrong fingerprint: want=%v got=%v", "shipment-not-found", got)
+	}
+}
+
+func TestIsStandardLibrary(t *testing.T) {
+	for function, want := range map[string]bool{
+		"runtime.goexit":         true,
+		"testing.tRunner":        true,
+		"net/http.(*conn).serve": true,
+		"main.main":              false,
+		"github.com/RoseRocket/xerrs.TestFingerprint": false,
+	} {
+		if got := isStandardLibrary(function); got != want {
+			t.Errorf("wrong result for %v: want=%v got=%v", function, want, got)
+		}
+	}
+}
+
+func TestNormalizeMessage(t *testing.T) {
+	for message, want := range map[string]string{
+		"shipment 123 not found": "shipment <n> not found",
+		"bad pointer 0x1f":       "bad pointer <hex>",
+		"order 0b9e5f6a-1c2d-4e3f-8a9b-0c1d2e3f4a5b is not approved": "order <uuid> is not approved",
+		"connection refused": "connection refused",
+	} {
+		if got := NormalizeMessage(message); got != want {
+			t.Errorf("wrong normalized message: want=%v got=%v", want, got)
+		}
+	}
+}
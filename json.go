@@ -0,0 +1,87 @@
+package xerrs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// jsonError is the JSON representation of xerr. It contains the same
+// information as Details. Error is the internal message, see Internal, and
+// Type is the Go type of the root cause.
+type jsonError struct {
+	Error     string                 `json:"error"`
+	Cause     string                 `json:"cause"`
+	Mask      string                 `json:"mask,omitempty"`
+	Type      string                 `json:"type,omitempty"`
+	Severity  Severity               `json:"severity,omitempty"`
+	Time      *time.Time             `json:"time,omitempty"`
+	Goroutine uint64                 `json:"goroutine,omitempty"`
+	Template  string                 `json:"template,omitempty"`
+	Args      []interface{}          `json:"args,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Stack     []StackLocation        `json:"stack,omitempty"`
+}
+
+// MarshalJSON - encodes xerr with its cause, mask, root cause type, severity,
+// creation time and goroutine when tracked, template, args, data merged from
+// its xerr causes and stack
+// Sensitive data and registered scrubbers are applied to the output
+func (x *xerr) MarshalJSON() ([]byte, error) {
+	out := jsonError{
+		Error:    Scrub(Internal(x)),
+		Cause:    Scrub(Internal(x.cause)),
+		Type:     fmt.Sprintf("%T", rootCause(x)),
+		Template: Scrub(Template(x)),
+		Severity: SeverityOf(x),
+		Stack:    x.stack,
+	}
+
+	if x.mask != nil {
+		out.Mask = Scrub(x.mask.Error())
+	}
+
+	if created := Time(x); !created.IsZero() {
+		out.Time = &created
+		out.Goroutine = Goroutine(x)
+	}
+
+	args := Args(x)
+	if len(args) > 0 {
+		out.Args = make([]interface{}, len(args))
+		for i, arg := range args {
+			out.Args[i] = jsonValue(RedactValue("", arg))
+		}
+	}
+
+	if data := Data(x); len(data) > 0 {
+		out.Data = make(map[string]interface{}, len(data))
+		for key, value := range data {
+			out.Data[key] = jsonValue(RedactValue(key, value))
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+// rootCause returns the innermost error of the chain of err
+func rootCause(err error) error {
+	for next := errors.Unwrap(err); next != nil; next = errors.Unwrap(err) {
+		err = next
+	}
+
+	return err
+}
+
+// jsonValue converts values which encoding/json can not represent in a useful way
+func jsonValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Marshaler:
+		return value
+	case error:
+		return value.Error()
+	}
+
+	return v
+}
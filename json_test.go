@@ -0,0 +1,46 @@
+package xerrs
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	cause := Errorf("shipment %d not found", 123)
+	SetData(cause, "host", "db1")
+	SetData(cause, "attempt", 1)
+
+	err := Mask(Wrapf(cause, "carrier %q", "ups"), errors.New("not found"))
+	SetData(err, "attempt", 2)
+	SetData(err, "last", errors.New("timeout"))
+
+	b, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	var got map[string]interface{}
+	if e := json.Unmarshal(b, &got); e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	for key, want := range map[string]interface{}{
+		"error":    "carrier \"ups\": shipment 123 not found",
+		"cause":    "shipment 123 not found",
+		"mask":     "not found",
+		"template": "carrier %q: shipment %d not found",
+		"args":     []interface{}{"ups", float64(123)},
+		"data":     map[string]interface{}{"attempt": float64(2), "host": "db1", "last": "timeout"},
+	} {
+		if !reflect.DeepEqual(got[key], want) {
+			t.Errorf("wrong %s: want=%v got=%v", key, want, got[key])
+		}
+	}
+
+	stack, ok := got["stack"].([]interface{})
+	if !ok || len(stack) == 0 {
+		t.Errorf("expected stack, got=%v", got["stack"])
+	}
+}
@@ -0,0 +1,152 @@
+package xerrs
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+)
+
+// Matcher - a predicate on an error chain, see Match
+type Matcher func(err error) bool
+
+// Match - reports whether err matches every matcher. Matchers inspect the whole
+// chain, including errors joined with errors.Join, so it can be used in
+// switch-style dispatch:
+//
+//	switch {
+//	case xerrs.Match(err, xerrs.ByCode("shipment_not_found")):
+//	case xerrs.Match(err, xerrs.ByType[*net.OpError]()):
+//	}
+//
+// If err is nil then false is returned
+func Match(err error, matchers ...Matcher) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, matcher := range matchers {
+		if !matcher(err) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ByCode - matches chains with an error which has the code, set with WithCode
+// or by a Definition
+func ByCode(code string) Matcher {
+	return func(err error) bool {
+		return walk(err, func(err error) bool {
+			x, ok := err.(*xerr)
+			if !ok {
+				return false
+			}
+
+			return x.code == code || (x.def != nil && x.def.Code == code)
+		})
+	}
+}
+
+// ByType - matches chains with an error of type T, like errors.As
+func ByType[T error]() Matcher {
+	return func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}
+}
+
+// ByMessageRegexp - matches chains with an error whose message matches the
+// regular expression. The message of xerr is its internal message, see Internal.
+// It panics if pattern does not compile.
+func ByMessageRegexp(pattern string) Matcher {
+	re := regexp.MustCompile(pattern)
+
+	return func(err error) bool {
+		return walk(err, func(err error) bool {
+			return re.MatchString(Internal(err))
+		})
+	}
+}
+
+// ByData - matches chains with an error whose data stored at name is equal to
+// value, compared with reflect.DeepEqual
+func ByData(name string, value interface{}) Matcher {
+	return func(err error) bool {
+		return walk(err, func(err error) bool {
+			x, ok := err.(*xerr)
+			if !ok {
+				return false
+			}
+
+			v, ok := x.data[name]
+			return ok && reflect.DeepEqual(v, value)
+		})
+	}
+}
+
+// BySentinel - matches chains which contain target, like errors.Is. It also
+// matches errors created from a Definition.
+func BySentinel(target error) Matcher {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
+
+// Any - matches chains which match at least one of matchers
+func Any(matchers ...Matcher) Matcher {
+	return func(err error) bool {
+		for _, matcher := range matchers {
+			if matcher(err) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// All - matches chains which match every matcher
+func All(matchers ...Matcher) Matcher {
+	return func(err error) bool {
+		for _, matcher := range matchers {
+			if !matcher(err) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Not - matches chains which do not match matcher
+func Not(matcher Matcher) Matcher {
+	return func(err error) bool {
+		return !matcher(err)
+	}
+}
+
+// walk reports whether fn returns true for err or any error it wraps
+func walk(err error, fn func(error) bool) bool {
+	for err != nil {
+		if fn(err) {
+			return true
+		}
+
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				if walk(inner, fn) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package xerrs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+var errTestMatchNotFound = Define(Definition{Code: "match_test_not_found", Message: "shipment %d not found"})
+
+func TestMatch(t *testing.T) {
+	notFound := Wrap(errTestMatchNotFound.New(7, WithData("carrier", "ups")), "load")
+	pathErr := Extend(&fs.PathError{Op: "open", Path: "/tmp/a", Err: fs.ErrNotExist}, WithCode("fs"))
+	joined := errors.Join(errors.New("first"), fmt.Errorf("second: %w", pathErr))
+	sameMessage := New("shipment 7 not found")
+
+	cases := []struct {
+		name     string
+		err      error
+		matchers []Matcher
+		want     bool
+	}{
+		{"no matchers", notFound, nil, true},
+		{"nil error", nil, []Matcher{Not(ByCode("x"))}, false},
+		{"code", notFound, []Matcher{ByCode("match_test_not_found")}, true},
+		{"code option", pathErr, []Matcher{ByCode("fs")}, true},
+		{"wrong code", notFound, []Matcher{ByCode("fs")}, false},
+		{"type", pathErr, []Matcher{ByType[*fs.PathError]()}, true},
+		{"wrong type", notFound, []Matcher{ByType[*fs.PathError]()}, false},
+		{"message", notFound, []Matcher{ByMessageRegexp(`^shipment \d+ not found$`)}, true},
+		{"wrong message", notFound, []Matcher{ByMessageRegexp(`^timeout`)}, false},
+		{"data", notFound, []Matcher{ByData("carrier", "ups")}, true},
+		{"wrong data", notFound, []Matcher{ByData("carrier", "fedex")}, false},
+		{"sentinel", pathErr, []Matcher{BySentinel(fs.ErrNotExist)}, true},
+		{"definition", notFound, []Matcher{BySentinel(errTestMatchNotFound)}, true},
+		{"same message is not the sentinel", sameMessage, []Matcher{BySentinel(errTestMatchNotFound)}, false},
+		{"joined", joined, []Matcher{ByCode("fs"), ByType[*fs.PathError](), ByMessageRegexp("^first$")}, true},
+		{"any", notFound, []Matcher{Any(ByCode("fs"), ByData("carrier", "ups"))}, true},
+		{"any none", notFound, []Matcher{Any(ByCode("fs"), ByData("carrier", "fedex"))}, false},
+		{"all", notFound, []Matcher{All(ByCode("match_test_not_found"), ByData("carrier", "ups"))}, true},
+		{"all but one", notFound, []Matcher{All(ByCode("match_test_not_found"), ByCode("fs"))}, false},
+		{"not", notFound, []Matcher{Not(ByCode("fs"))}, true},
+		{"every matcher", notFound, []Matcher{ByCode("match_test_not_found"), ByCode("fs")}, false},
+	}
+
+	for _, c := range cases {
+		if got := Match(c.err, c.matchers...); got != c.want {
+			t.Errorf("wrong result for %s: want=%v got=%v", c.name, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package xerrs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Params - named parameters of a Message
+type Params map[string]interface{}
+
+// Message - a localizable public message which can be used as a mask.
+// Text refers to parameters by name, e.g. "Shipment {id} was not found".
+type Message struct {
+	ID      string
+	Default string
+	Params  Params
+}
+
+// NewMessage - creates a Message with a default text which is used when no
+// translation is found
+func NewMessage(id, defaultText string) Message {
+	return Message{ID: id, Default: defaultText}
+}
+
+// With - returns a copy of the message with params added
+func (m Message) With(params Params) Message {
+	merged := make(Params, len(m.Params)+len(params))
+	for name, value := range m.Params {
+		merged[name] = value
+	}
+	for name, value := range params {
+		merged[name] = value
+	}
+
+	m.Params = merged
+	return m
+}
+
+// Error - returns the default text with params substituted
+func (m Message) Error() string {
+	return m.Render(m.Default)
+}
+
+// Render - substitutes {name} placeholders in text with the message params
+// Unknown placeholders are left as is
+func (m Message) Render(text string) string {
+	if len(m.Params) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+
+	pairs := make([]string, 0, 2*len(m.Params))
+	for name, value := range m.Params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Catalog - source of translated message texts
+type Catalog interface {
+	// Lookup returns the text of message id in locale
+	Lookup(locale, id string) (string, bool)
+}
+
+// MapCatalog - a Catalog of texts by locale and message id
+type MapCatalog map[string]map[string]string
+
+// Lookup - returns the text of message id in locale
+// Locales are compared case-insensitively and "_" matches "-"
+func (c MapCatalog) Lookup(locale, id string) (string, bool) {
+	if text, ok := c[locale][id]; ok {
+		return text, true
+	}
+
+	locale = normalizeLocale(locale)
+	for key, texts := range c {
+		if normalizeLocale(key) == locale {
+			if text, ok := texts[id]; ok {
+				return text, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// LoadJSON - adds texts of locale from a JSON object of message id to text
+func (c MapCatalog) LoadJSON(locale string, r io.Reader) error {
+	texts := make(map[string]string)
+	if err := json.NewDecoder(r).Decode(&texts); err != nil {
+		return Wrapf(err, "decode %s messages", locale)
+	}
+
+	locale = normalizeLocale(locale)
+	if c[locale] == nil {
+		c[locale] = make(map[string]string, len(texts))
+	}
+
+	for id, text := range texts {
+		c[locale][id] = text
+	}
+
+	return nil
+}
+
+// LoadCatalog - loads every "<locale>.json" file in dir of fsys into a MapCatalog
+func LoadCatalog(fsys fs.FS, dir string) (MapCatalog, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, Extend(err)
+	}
+
+	catalog := make(MapCatalog)
+	for _, name := range files {
+		f, err := fsys.Open(name)
+		if err != nil {
+			return nil, Extend(err)
+		}
+
+		err = catalog.LoadJSON(strings.TrimSuffix(path.Base(name), ".json"), f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return catalog, nil
+}
+
+// DefaultLocale - locale used when a message has no translation in the requested one
+var DefaultLocale = "en"
+
+var catalog = struct {
+	sync.RWMutex
+	Catalog
+}{}
+
+// SetCatalog - sets the Catalog used by PublicMessage
+func SetCatalog(c Catalog) {
+	catalog.Lock()
+	defer catalog.Unlock()
+
+	catalog.Catalog = c
+}
+
+// PublicMessage - returns the mask of err rendered in locale
+// If the mask is a Message then its translation is looked up in locale, then in
+// its base language (e.g. "fr" for "fr-CA"), then in DefaultLocale, and finally
+// its default text is used
+// If the mask is not a Message then mask.Error() is returned
+// If err has no mask then "" is returned
+func PublicMessage(err error, locale string) string {
+	mask := findMask(err)
+	if mask == nil {
+		return ""
+	}
+
+	var m Message
+	if !errors.As(mask, &m) {
+		return mask.Error()
+	}
+
+	catalog.RLock()
+	c := catalog.Catalog
+	catalog.RUnlock()
+
+	if c == nil {
+		return m.Error()
+	}
+
+	for _, candidate := range fallbackLocales(locale) {
+		if text, ok := c.Lookup(candidate, m.ID); ok {
+			return m.Render(text)
+		}
+	}
+
+	return m.Error()
+}
+
+// findMask returns the outermost mask in the xerr chain
+func findMask(err error) error {
+	for err != nil {
+		x, ok := err.(*xerr)
+		if !ok {
+			return nil
+		}
+
+		if x.mask != nil {
+			return x.mask
+		}
+
+		err = x.cause
+	}
+
+	return nil
+}
+
+// fallbackLocales returns the locales searched for a translation, most specific first
+func fallbackLocales(locale string) []string {
+	locale = normalizeLocale(locale)
+
+	var locales []string
+	for locale != "" {
+		locales = append(locales, locale)
+
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+
+	if defaultLocale := normalizeLocale(DefaultLocale); defaultLocale != "" {
+		locales = append(locales, defaultLocale)
+	}
+
+	return locales
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
@@ -0,0 +1,66 @@
+package xerrs
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPublicMessage(t *testing.T) {
+	catalog, err := LoadCatalog(fstest.MapFS{
+		"locales/en.json":    {Data: []byte(`{"shipment.not_found": "Shipment {id} was not found"}`)},
+		"locales/fr.json":    {Data: []byte(`{"shipment.not_found": "L'envoi {id} est introuvable"}`)},
+		"locales/fr-CA.json": {Data: []byte(`{}`)},
+	}, "locales")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	SetCatalog(catalog)
+	defer SetCatalog(nil)
+
+	notFound := NewMessage("shipment.not_found", "Shipment {id} not found")
+	cause := errors.New("sql: no rows in result set")
+	masked := Wrap(Mask(cause, notFound.With(Params{"id": 42})), "load")
+
+	for _, test := range []struct {
+		description string
+		in          error
+		locale      string
+		want        string
+	}{
+		{"no mask", Extend(cause), "en", ""},
+		{"plain mask", Mask(cause, errors.New("not found")), "fr", "not found"},
+		{"exact locale", masked, "fr", "L'envoi 42 est introuvable"},
+		{"base language", masked, "fr_CA", "L'envoi 42 est introuvable"},
+		{"default locale", masked, "de", "Shipment 42 was not found"},
+		{"unknown message", Mask(cause, NewMessage("unknown", "Something went wrong")), "fr", "Something went wrong"},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			if got := PublicMessage(test.in, test.locale); got != test.want {
+				t.Errorf("wrong public message: want=%v got=%v", test.want, got)
+			}
+		})
+	}
+
+	if Cause(Cause(masked)) != cause {
+		t.Errorf("expected cause to be untouched")
+	}
+	if got := masked.Error(); got != "load: Shipment 42 not found" {
+		t.Errorf("wrong error message: want=%v got=%v", "load: Shipment 42 not found", got)
+	}
+}
+
+func TestMapCatalogLookup(t *testing.T) {
+	catalog := MapCatalog{"fr-CA": {"shipment.not_found": "Envoi introuvable"}}
+
+	for _, locale := range []string{"fr-CA", "fr-ca", "fr_CA"} {
+		if got, ok := catalog.Lookup(locale, "shipment.not_found"); !ok || got != "Envoi introuvable" {
+			t.Errorf("wrong text for %s: want=%v got=%v", locale, "Envoi introuvable", got)
+		}
+	}
+
+	if _, ok := catalog.Lookup("fr", "shipment.not_found"); ok {
+		t.Errorf("expected no text for fr")
+	}
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"expvar"
+)
+
+// Expvar - counts errors in an expvar map with one nested map per dimension:
+// "code", "severity", "type" and "fingerprint", and the "total" count
+type Expvar struct {
+	total       *expvar.Int
+	code        *expvar.Map
+	severity    *expvar.Map
+	errorType   *expvar.Map
+	fingerprint *expvar.Map
+}
+
+// NewExpvar - returns a counter published under name, e.g. "xerrs_errors"
+// If name is already published by NewExpvar then the counts are shared
+func NewExpvar(name string) *Expvar {
+	root, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		root = expvar.NewMap(name)
+	}
+
+	return &Expvar{
+		total:       child(root, "total", new(expvar.Int)),
+		code:        child(root, "code", new(expvar.Map).Init()),
+		severity:    child(root, "severity", new(expvar.Map).Init()),
+		errorType:   child(root, "type", new(expvar.Map).Init()),
+		fingerprint: child(root, "fingerprint", new(expvar.Map).Init()),
+	}
+}
+
+// child returns the variable stored at key in parent, storing v first if
+// there is none
+func child[T expvar.Var](parent *expvar.Map, key string, v T) T {
+	if existing, ok := parent.Get(key).(T); ok {
+		return existing
+	}
+
+	parent.Set(key, v)
+	return v
+}
+
+// Count - counts err
+// If err is nil then nothing happens
+func (e *Expvar) Count(err error) {
+	if err == nil {
+		return
+	}
+
+	labels := LabelsOf(err)
+
+	e.total.Add(1)
+	e.code.Add(labels.Code, 1)
+	e.severity.Add(labels.Severity, 1)
+	e.errorType.Add(labels.Type, 1)
+	e.fingerprint.Add(labels.Fingerprint, 1)
+}
+
+// Send - counts err, so that the counter can be used as a xerrs.Sink
+func (e *Expvar) Send(ctx context.Context, err error) error {
+	e.Count(err)
+	return nil
+}
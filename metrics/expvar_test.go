@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+// expvarRuns makes the expvar name of every run of TestExpvar unique, since
+// expvar variables can not be removed
+var expvarRuns int
+
+func TestExpvar(t *testing.T) {
+	expvarRuns++
+	name := fmt.Sprintf("metrics_test_errors_%d", expvarRuns)
+	counter := NewExpvar(name)
+
+	err := xerrs.New("shipment 7 not found", xerrs.WithCode("not_found"), xerrs.WithSeverity(xerrs.SeverityWarning))
+	counter.Count(err)
+	counter.Count(nil)
+	NewExpvar(name).Count(err)
+
+	server := httptest.NewServer(expvar.Handler())
+	defer server.Close()
+
+	resp, e := server.Client().Get(server.URL)
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+	defer resp.Body.Close()
+
+	var vars map[string]json.RawMessage
+	if e := json.NewDecoder(resp.Body).Decode(&vars); e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	var got struct {
+		Total       int            `json:"total"`
+		Code        map[string]int `json:"code"`
+		Severity    map[string]int `json:"severity"`
+		Type        map[string]int `json:"type"`
+		Fingerprint map[string]int `json:"fingerprint"`
+	}
+	if e := json.Unmarshal(vars[name], &got); e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	if got.Total != 2 {
+		t.Errorf("wrong total: want=%v got=%v", 2, got.Total)
+	}
+	if got.Code["not_found"] != 2 || got.Severity["warning"] != 2 || got.Type["*errors.errorString"] != 2 {
+		t.Errorf("wrong counts: %+v", got)
+	}
+	if got.Fingerprint[xerrs.Fingerprint(err)] != 2 {
+		t.Errorf("wrong fingerprint count: want=%v got=%v", 2, got.Fingerprint)
+	}
+}
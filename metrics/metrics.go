@@ -0,0 +1,44 @@
+// Package metrics counts xerrs errors by code, severity, root cause type and
+// fingerprint, for dashboards which show the errors that spike after a deploy.
+//
+// Counters implement xerrs.Sink so they can be added to a Reporter:
+//
+//	counter := metrics.NewPrometheus()
+//	http.Handle("/metrics", counter)
+//	reporter := xerrs.NewReporter(xerrs.ReporterOptions{}, counter)
+package metrics
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+// Counter - counts errors
+type Counter interface {
+	Count(err error)
+}
+
+// Labels - the dimensions errors are counted by
+type Labels struct {
+	Code        string
+	Severity    string
+	Type        string
+	Fingerprint string
+}
+
+// LabelsOf - returns the code, severity, root cause type and fingerprint of err
+func LabelsOf(err error) Labels {
+	root := err
+	for next := errors.Unwrap(root); next != nil; next = errors.Unwrap(root) {
+		root = next
+	}
+
+	return Labels{
+		Code:        xerrs.Code(err),
+		Severity:    xerrs.SeverityOf(err).String(),
+		Type:        fmt.Sprintf("%T", root),
+		Fingerprint: xerrs.Fingerprint(err),
+	}
+}
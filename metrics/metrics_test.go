@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+func TestLabelsOf(t *testing.T) {
+	err := xerrs.Wrap(xerrs.New("shipment 7 not found", xerrs.WithCode("not_found"), xerrs.WithSeverity(xerrs.SeverityWarning)), "load")
+
+	want := Labels{
+		Code:        "not_found",
+		Severity:    "warning",
+		Type:        "*errors.errorString",
+		Fingerprint: xerrs.Fingerprint(err),
+	}
+	if got := LabelsOf(err); got != want {
+		t.Errorf("wrong labels: want=%+v got=%+v", want, got)
+	}
+
+	plain := errors.New("boom")
+	want = Labels{Type: "*errors.errorString", Fingerprint: xerrs.Fingerprint(plain)}
+	if got := LabelsOf(plain); got != want {
+		t.Errorf("wrong labels: want=%+v got=%+v", want, got)
+	}
+}
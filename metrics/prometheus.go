@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Prometheus - counts errors and serves them in the Prometheus text exposition
+// format as the xerrs_errors_total counter
+type Prometheus struct {
+	mu     sync.Mutex
+	counts map[Labels]uint64
+}
+
+// NewPrometheus - returns an empty Prometheus counter
+func NewPrometheus() *Prometheus {
+	return &Prometheus{counts: make(map[Labels]uint64)}
+}
+
+// Count - counts err
+// If err is nil then nothing happens
+func (p *Prometheus) Count(err error) {
+	if err == nil {
+		return
+	}
+
+	labels := LabelsOf(err)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.counts[labels]++
+}
+
+// Send - counts err, so that the counter can be used as a xerrs.Sink
+func (p *Prometheus) Send(ctx context.Context, err error) error {
+	p.Count(err)
+	return nil
+}
+
+// Counts - returns a copy of the counts
+func (p *Prometheus) Counts() map[Labels]uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	counts := make(map[Labels]uint64, len(p.counts))
+	for labels, count := range p.counts {
+		counts[labels] = count
+	}
+
+	return counts
+}
+
+// ServeHTTP - writes the counts in the Prometheus text exposition format
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	counts := p.Counts()
+
+	lines := make([]string, 0, len(counts))
+	for labels, count := range counts {
+		lines = append(lines, fmt.Sprintf(
+			"xerrs_errors_total{code=%s,severity=%s,type=%s,fingerprint=%s} %d",
+			labelValue(labels.Code),
+			labelValue(labels.Severity),
+			labelValue(labels.Type),
+			labelValue(labels.Fingerprint),
+			count,
+		))
+	}
+	sort.Strings(lines)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprintln(w, "# HELP xerrs_errors_total Number of errors by code, severity, root cause type and fingerprint.")
+	fmt.Fprintln(w, "# TYPE xerrs_errors_total counter")
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelValue quotes a label value as the exposition format requires
+func labelValue(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+func TestPrometheus(t *testing.T) {
+	counter := NewPrometheus()
+
+	notFound := xerrs.New("shipment 7 not found", xerrs.WithCode("not_found"), xerrs.WithSeverity(xerrs.SeverityWarning))
+	for i := 0; i < 2; i++ {
+		counter.Count(notFound)
+	}
+	counter.Count(nil)
+
+	quoted := fmt.Errorf("bad \"input\"")
+	counter.Send(context.Background(), quoted)
+
+	server := httptest.NewServer(counter)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	want := "# HELP xerrs_errors_total Number of errors by code, severity, root cause type and fingerprint.\n" +
+		"# TYPE xerrs_errors_total counter\n" +
+		fmt.Sprintf("xerrs_errors_total{code=\"\",severity=\"\",type=\"*errors.errorString\",fingerprint=%q} 1\n", xerrs.Fingerprint(quoted)) +
+		fmt.Sprintf("xerrs_errors_total{code=\"not_found\",severity=\"warning\",type=\"*errors.errorString\",fingerprint=%q} 2\n", xerrs.Fingerprint(notFound))
+	if string(body) != want {
+		t.Errorf("wrong exposition: want=%v got=%v", want, string(body))
+	}
+
+	if got := resp.Header.Get("Content-Type"); got != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("wrong content type: %v", got)
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	if got, want := labelValue("a\\b\"c\nd"), `"a\\b\"c\nd"`; got != want {
+		t.Errorf("wrong label value: want=%v got=%v", want, got)
+	}
+}
@@ -0,0 +1,205 @@
+package xerrs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Normalizer - rewrites stacks, Details and JSON output into a form which does
+// not depend on the machine, the line numbers or the test runner, for golden
+// file tests. Times and goroutine IDs become zero values, so normalized output
+// can still be parsed.
+type Normalizer struct {
+	// Root is the directory file names are made relative to. Defaults to the
+	// root of the module containing the working directory. Files of the
+	// standard library and the module cache are relative to their source roots.
+	Root string
+	// KeepLines keeps line numbers, which are replaced with 0 otherwise.
+	KeepLines bool
+	// KeepRuntime keeps frames of the runtime and testing packages, which are
+	// left out otherwise.
+	KeepRuntime bool
+}
+
+// NormalizeStack - returns a normalized copy of stack, see Normalizer
+func NormalizeStack(stack []StackLocation) []StackLocation {
+	return Normalizer{}.Stack(stack)
+}
+
+// NormalizeDetails - returns text printed by Details with normalized stack
+// locations, time and goroutine, see Normalizer
+func NormalizeDetails(text string) string {
+	return Normalizer{}.Details(text)
+}
+
+// NormalizeJSON - returns the JSON encoding of errors with normalized stacks,
+// times and goroutines, see Normalizer. Errors may be nested in other objects.
+func NormalizeJSON(data []byte) ([]byte, error) {
+	return Normalizer{}.JSON(data)
+}
+
+// Stack - returns a normalized copy of stack
+func (n Normalizer) Stack(stack []StackLocation) []StackLocation {
+	root := n.root()
+
+	result := make([]StackLocation, 0, len(stack))
+	for _, location := range stack {
+		if !n.KeepRuntime && isRuntimeFrame(location.Function) {
+			continue
+		}
+
+		location.File = relativeFile(root, location.Function, location.File)
+		if !n.KeepLines {
+			location.Line = 0
+		}
+		result = append(result, location)
+	}
+
+	return result
+}
+
+// Details - returns text printed by Details with normalized stack locations,
+// time and goroutine. Other lines are left as they are.
+func (n Normalizer) Details(text string) string {
+	lines := strings.Split(text, "\n")
+
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, detailsTime):
+			line = detailsTime + time.Time{}.Format(time.RFC3339Nano)
+		case strings.HasPrefix(line, detailsGoroutine):
+			line = detailsGoroutine + "0"
+		default:
+			location, ok := ParseStackLocation(line)
+			if !ok || location.String() != line {
+				break
+			}
+
+			normalized := n.Stack([]StackLocation{location})
+			if len(normalized) == 0 {
+				continue
+			}
+			line = normalized[0].String()
+		}
+
+		result = append(result, line)
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// JSON - returns data with the stacks, times and goroutines of every encoded
+// error normalized. Objects are encoded with sorted keys.
+func (n Normalizer) JSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, Extend(err)
+	}
+
+	encoded, err := json.Marshal(n.jsonValue(value))
+	return encoded, Extend(err)
+}
+
+// jsonValue normalizes the objects in value which look like encoded errors
+func (n Normalizer) jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = n.jsonValue(v[i])
+		}
+	case map[string]interface{}:
+		_, isError := v["cause"]
+		for key, field := range v {
+			switch {
+			case isError && key == "stack":
+				v[key] = n.jsonStack(field)
+			case isError && key == "time":
+				v[key] = time.Time{}
+			case isError && key == "goroutine":
+				v[key] = 0
+			case isError && key == "data":
+				// custom data is left as it is
+			default:
+				v[key] = n.jsonValue(field)
+			}
+		}
+	}
+
+	return value
+}
+
+func (n Normalizer) jsonStack(value interface{}) interface{} {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var stack []StackLocation
+	if err := json.Unmarshal(encoded, &stack); err != nil {
+		return value
+	}
+
+	return n.Stack(stack)
+}
+
+func (n Normalizer) root() string {
+	if n.Root != "" {
+		return n.Root
+	}
+
+	return moduleRoot()
+}
+
+var moduleRoot = sync.OnceValue(func() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for current := dir; ; {
+		if _, err := os.Stat(filepath.Join(current, "go.mod")); err == nil {
+			return current
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir
+		}
+		current = parent
+	}
+})
+
+// relativeFile returns file relative to root, or to the source root of the
+// standard library or the module cache. Other files keep their base name only.
+func relativeFile(root, function, file string) string {
+	file = filepath.ToSlash(file)
+
+	if i := strings.LastIndex(file, "/pkg/mod/"); i >= 0 {
+		return file[i+len("/pkg/mod/"):]
+	}
+
+	if isStandardLibrary(function) {
+		if i := strings.LastIndex(file, "/src/"); i >= 0 {
+			return file[i+len("/src/"):]
+		}
+	}
+
+	if root != "" {
+		rel, err := filepath.Rel(root, filepath.FromSlash(file))
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	return filepath.Base(file)
+}
+
+// isRuntimeFrame reports whether function belongs to the runtime or testing packages
+func isRuntimeFrame(function string) bool {
+	return strings.HasPrefix(function, "runtime.") || strings.HasPrefix(function, "testing.")
+}
@@ -0,0 +1,83 @@
+package xerrs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizeStack(t *testing.T) {
+	stack := []StackLocation{
+		{Function: "github.com/RoseRocket/xerrs.TestNormalizeStack", File: moduleRoot() + "/normalize_test.go", Line: 12},
+		{Function: "github.com/RoseRocket/xerrs/retry.Do", File: moduleRoot() + "/retry/retry.go", Line: 30},
+		{Function: "example.com/lib.Do", File: "/home/ci/go/pkg/mod/example.com/lib@v1.0.0/lib.go", Line: 5},
+		{Function: "net/http.HandlerFunc.ServeHTTP", File: "/usr/local/go/src/net/http/server.go", Line: 2000},
+		{Function: "example.com/other.Do", File: "/elsewhere/other.go", Line: 7},
+		{Function: "testing.tRunner", File: "/usr/local/go/src/testing/testing.go", Line: 1000},
+		{Function: "runtime.goexit", File: "/usr/local/go/src/runtime/asm_amd64.s", Line: 1650},
+	}
+
+	want := []StackLocation{
+		{Function: "github.com/RoseRocket/xerrs.TestNormalizeStack", File: "normalize_test.go"},
+		{Function: "github.com/RoseRocket/xerrs/retry.Do", File: "retry/retry.go"},
+		{Function: "example.com/lib.Do", File: "example.com/lib@v1.0.0/lib.go"},
+		{Function: "net/http.HandlerFunc.ServeHTTP", File: "net/http/server.go"},
+		{Function: "example.com/other.Do", File: "other.go"},
+	}
+	if got := NormalizeStack(stack); !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong stack: want=%v got=%v", want, got)
+	}
+
+	got := Normalizer{KeepLines: true, KeepRuntime: true}.Stack(stack)
+	if len(got) != len(stack) || got[0].Line != 12 || got[6].File != "runtime/asm_amd64.s" {
+		t.Errorf("expected lines and runtime frames to be kept, got=%v", got)
+	}
+
+	if stack[0].Line != 12 {
+		t.Errorf("expected the stack not to be modified")
+	}
+}
+
+func TestNormalizeDetails(t *testing.T) {
+	EnableTracking(true)
+	defer EnableTracking(false)
+
+	err := New("ABC")
+	SetData(err, "id", 7)
+
+	want := "\n[ERROR] ABC\n[TIME] 0001-01-01T00:00:00Z\n[GOROUTINE] 0\n[DATA]:\nid: 7\n[STACK]:\n" +
+		"github.com/RoseRocket/xerrs.TestNormalizeDetails [normalize_test.go:0]"
+	if got := NormalizeDetails(Details(err, 10)); got != want {
+		t.Errorf("wrong details: want=%q got=%q", want, got)
+	}
+}
+
+func TestNormalizeJSON(t *testing.T) {
+	EnableTracking(true)
+	defer EnableTracking(false)
+
+	err := New("ABC")
+	SetData(err, "time", "kept")
+
+	line, e := json.Marshal(map[string]interface{}{"msg": "failed", "err": err, "time": "kept"})
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	got, e := NormalizeJSON(line)
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	want := `{"err":{"cause":"ABC","data":{"time":"kept"},"error":"ABC","goroutine":0,` +
+		`"stack":[{"function":"github.com/RoseRocket/xerrs.TestNormalizeJSON","file":"normalize_test.go","line":0}],` +
+		`"template":"ABC","time":"` + time.Time{}.Format(time.RFC3339Nano) + `","type":"*errors.errorString"},"msg":"failed","time":"kept"}`
+	if string(got) != want {
+		t.Errorf("wrong JSON: want=%v got=%v", want, string(got))
+	}
+
+	if _, e := NormalizeJSON([]byte("{")); e == nil {
+		t.Errorf("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,101 @@
+package xerrs
+
+// Option - annotates an error while it is created by New, Errorf, Extend, Wrap,
+// Wrapf or a Definition. Errorf, Wrapf and Definition accept options among
+// their format arguments.
+type Option func(*options)
+
+type options struct {
+	x       *xerr
+	skip    int
+	noStack bool
+}
+
+// WithCode - sets the code of the error, see Code
+func WithCode(code string) Option {
+	return func(o *options) {
+		o.x.code = code
+	}
+}
+
+// WithData - sets custom data stored in the error, see SetData
+func WithData(name string, value interface{}) Option {
+	return func(o *options) {
+		SetData(o.x, name, value)
+	}
+}
+
+// WithMask - sets the mask of the error, see Mask
+func WithMask(mask error) Option {
+	return func(o *options) {
+		o.x.mask = mask
+	}
+}
+
+// WithSeverity - sets the severity of the error, see SetSeverity
+func WithSeverity(severity Severity) Option {
+	return func(o *options) {
+		o.x.severity = severity
+	}
+}
+
+// WithSkip - leaves the n innermost stack locations out of the stack, so that
+// helper functions which create errors do not show up as their origin.
+// A negative total skip is treated as 0, the caller of the constructor.
+func WithSkip(n int) Option {
+	return func(o *options) {
+		o.skip += n
+	}
+}
+
+// NoStack - creates the error without a stack, e.g. for errors which are
+// expected and frequent
+func NoStack() Option {
+	return func(o *options) {
+		o.noStack = true
+	}
+}
+
+// build applies opts to x, sets the stack of the caller of the constructor
+// which called build and tracks x
+func build(x *xerr, opts []Option) *xerr {
+	o := options{x: x}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	if o.skip < 0 {
+		o.skip = 0
+	}
+
+	if !o.noStack {
+		x.stack = getStack(stackFunctionOffset + 1 + o.skip)
+	}
+
+	return track(x)
+}
+
+// splitOptions separates the options from the format arguments in args
+func splitOptions(args []interface{}) ([]interface{}, []Option) {
+	var opts []Option
+	for _, arg := range args {
+		if opt, ok := arg.(Option); ok {
+			opts = append(opts, opt)
+		}
+	}
+
+	if len(opts) == 0 {
+		return args, nil
+	}
+
+	rest := make([]interface{}, 0, len(args)-len(opts))
+	for _, arg := range args {
+		if _, ok := arg.(Option); !ok {
+			rest = append(rest, arg)
+		}
+	}
+
+	return rest, opts
+}
@@ -0,0 +1,94 @@
+package xerrs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errTestOptionsDefinition = Define(Definition{Code: "options_test_definition", Message: "ABC %d"})
+
+func newWithSkip() error {
+	return New("ABC", WithSkip(1))
+}
+
+func TestOptions(t *testing.T) {
+	mask := errors.New("masked")
+	opts := []Option{
+		WithCode("db"),
+		WithData("id", 7),
+		WithMask(mask),
+		WithSeverity(SeverityWarning),
+	}
+
+	cases := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{"New", New("ABC", opts...), "ABC"},
+		{"Errorf", Errorf("ABC %d", 7, opts[0], opts[1], opts[2], opts[3]), "ABC 7"},
+		{"Extend", Extend(errors.New("ABC"), opts...), "ABC"},
+		{"Wrap", Wrap(errors.New("ABC"), "wrapped", opts...), "wrapped: ABC"},
+		{"Wrapf", Wrapf(errors.New("ABC"), "wrapped %d", 7, opts[0], opts[1], opts[2], opts[3]), "wrapped 7: ABC"},
+	}
+
+	for _, c := range cases {
+		if got := Internal(c.err); got != c.message {
+			t.Errorf("wrong message for %s: want=%v got=%v", c.name, c.message, got)
+		}
+		if got := Code(c.err); got != "db" {
+			t.Errorf("wrong code for %s: want=%v got=%v", c.name, "db", got)
+		}
+		if got, _ := GetData(c.err, "id"); got != 7 {
+			t.Errorf("wrong data for %s: want=%v got=%v", c.name, 7, got)
+		}
+		if got := c.err.Error(); got != "masked" {
+			t.Errorf("wrong mask for %s: want=%v got=%v", c.name, "masked", got)
+		}
+		if got := SeverityOf(c.err); got != SeverityWarning {
+			t.Errorf("wrong severity for %s: want=%v got=%v", c.name, SeverityWarning, got)
+		}
+		if got := Stack(c.err)[0].Function; got != "github.com/RoseRocket/xerrs.TestOptions" {
+			t.Errorf("wrong origin for %s: want=%v got=%v", c.name, "github.com/RoseRocket/xerrs.TestOptions", got)
+		}
+	}
+
+	if got := Args(Errorf("ABC %d", 7, WithCode("db"))); len(got) != 1 || got[0] != 7 {
+		t.Errorf("expected options to be left out of args, got=%v", got)
+	}
+}
+
+func TestWithSkip(t *testing.T) {
+	if got := Stack(newWithSkip())[0].Function; got != "github.com/RoseRocket/xerrs.TestWithSkip" {
+		t.Errorf("wrong origin: want=%v got=%v", "github.com/RoseRocket/xerrs.TestWithSkip", got)
+	}
+	if got := Stack(New("ABC", WithSkip(-3)))[0].Function; got != "github.com/RoseRocket/xerrs.TestWithSkip" {
+		t.Errorf("wrong origin for negative skip: want=%v got=%v", "github.com/RoseRocket/xerrs.TestWithSkip", got)
+	}
+	if got := Stack(New("ABC", WithSkip(2), WithSkip(-2)))[0].Function; got != "github.com/RoseRocket/xerrs.TestWithSkip" {
+		t.Errorf("wrong origin for balanced skips: want=%v got=%v", "github.com/RoseRocket/xerrs.TestWithSkip", got)
+	}
+}
+
+func TestNoStack(t *testing.T) {
+	err := Extend(fmt.Errorf("ABC"), NoStack())
+	if got := Stack(err); len(got) != 0 {
+		t.Errorf("expected no stack, got=%v", got)
+	}
+	if got := Details(err, 5); got != "\n[ERROR] ABC" {
+		t.Errorf("wrong details: want=%q got=%q", "\n[ERROR] ABC", got)
+	}
+}
+
+func TestCodeWithDefinition(t *testing.T) {
+	if got := Code(Wrap(errTestOptionsDefinition.New(1), "wrapped", WithCode("outer"))); got != "outer" {
+		t.Errorf("wrong code: want=%v got=%v", "outer", got)
+	}
+	if got := Code(Wrap(errTestOptionsDefinition.New(1, WithCode("inner")), "wrapped")); got != "inner" {
+		t.Errorf("wrong code: want=%v got=%v", "inner", got)
+	}
+	if got := Code(Wrap(New("ABC", WithCode("inner")), "wrapped", WithCode("outer"))); got != "outer" {
+		t.Errorf("wrong code: want=%v got=%v", "outer", got)
+	}
+}
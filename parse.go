@@ -0,0 +1,249 @@
+package xerrs
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record - an error read back from its text or JSON representation. The fields
+// mirror the JSON encoding of xerr so a JSON line decodes into a Record as is.
+// Records parsed from Details have no Error, Type, Template and Args because
+// Details does not print them, and their data values are strings.
+type Record struct {
+	Error     string                 `json:"error,omitempty"`
+	Cause     string                 `json:"cause"`
+	Mask      string                 `json:"mask,omitempty"`
+	Type      string                 `json:"type,omitempty"`
+	Severity  Severity               `json:"severity,omitempty"`
+	Time      *time.Time             `json:"time,omitempty"`
+	Goroutine uint64                 `json:"goroutine,omitempty"`
+	Template  string                 `json:"template,omitempty"`
+	Args      []interface{}          `json:"args,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Stack     []StackLocation        `json:"stack,omitempty"`
+}
+
+// Details - returns the record in the format of Details with at most maxStack
+// stack locations
+func (r Record) Details(maxStack int) string {
+	result := append([]string{""}, r.detailsHeader()...)
+
+	if len(r.Stack) == 0 {
+		return strings.Join(result, "\n")
+	}
+
+	result = append(result, detailsStack)
+	for i := 0; i < maxStack && i < len(r.Stack); i++ {
+		result = append(result, r.Stack[i].String())
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// Markers of the sections printed by Details
+const (
+	detailsError     = "[ERROR] "
+	detailsMask      = "[MASK ERROR] "
+	detailsSeverity  = "[SEVERITY] "
+	detailsTime      = "[TIME] "
+	detailsGoroutine = "[GOROUTINE] "
+	detailsData      = "[DATA]:"
+	detailsStack     = "[STACK]:"
+)
+
+var stackLocationPattern = regexp.MustCompile(`(\S+) \[([^\[\]]+):(\d+)\]\s*$`)
+
+// ParseStackLocation - parses a line printed by StackLocation.String
+// Text before the function name, such as a log prefix, is ignored
+func ParseStackLocation(line string) (StackLocation, bool) {
+	match := stackLocationPattern.FindStringSubmatch(line)
+	if match == nil {
+		return StackLocation{}, false
+	}
+
+	number, err := strconv.Atoi(match[3])
+	if err != nil {
+		return StackLocation{}, false
+	}
+
+	return StackLocation{Function: match[1], File: match[2], Line: number}, true
+}
+
+// ParseDetails - parses the first error printed by Details in text
+// If text does not contain an [ERROR] line then an error is returned
+func ParseDetails(text string) (Record, error) {
+	scanner := NewDetailsScanner(strings.NewReader(text))
+	if scanner.Scan() {
+		return scanner.Record(), nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Record{}, err
+	}
+
+	return Record{}, New("no [ERROR] line found")
+}
+
+// DetailsScanner - reads the errors printed by Details from a log, like
+// bufio.Scanner reads lines. Lines of other log messages are skipped.
+// Log prefixes in front of the Details lines are ignored and truncated stacks
+// are returned as far as they go.
+type DetailsScanner struct {
+	lines   *bufio.Scanner
+	pending string
+	held    bool
+	record  Record
+	err     error
+}
+
+// NewDetailsScanner - returns a DetailsScanner reading from r
+func NewDetailsScanner(r io.Reader) *DetailsScanner {
+	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	return &DetailsScanner{lines: lines}
+}
+
+// Record - returns the record read by the last call to Scan
+func (s *DetailsScanner) Record() Record {
+	return s.record
+}
+
+// Err - returns the first read error
+func (s *DetailsScanner) Err() error {
+	return s.err
+}
+
+// Scan - reads the next record and reports whether there was one
+func (s *DetailsScanner) Scan() bool {
+	var prefix string
+	for {
+		line, ok := s.next()
+		if !ok {
+			return false
+		}
+
+		if i := strings.Index(line, detailsError); i >= 0 {
+			prefix = line[:i]
+			s.record = Record{Cause: strings.TrimSpace(line[i+len(detailsError):])}
+			break
+		}
+	}
+
+	section := ""
+	for {
+		line, ok := s.next()
+		if !ok {
+			return true
+		}
+
+		if strings.Contains(line, detailsError) {
+			s.hold(line)
+			return true
+		}
+
+		text, ok := stripPrefix(line, prefix)
+		if !ok || !s.parseLine(text, &section) {
+			s.hold(line)
+			return true
+		}
+	}
+}
+
+// parseLine adds one line to the record, section is the current section
+// If the line does not belong to the record then false is returned
+func (s *DetailsScanner) parseLine(text string, section *string) bool {
+	r := &s.record
+
+	switch {
+	case strings.HasPrefix(text, detailsMask) && *section == "":
+		r.Mask = strings.TrimSpace(text[len(detailsMask):])
+	case strings.HasPrefix(text, detailsSeverity) && *section == "":
+		severity, err := ParseSeverity(text[len(detailsSeverity):])
+		if err != nil {
+			return false
+		}
+		r.Severity = severity
+	case strings.HasPrefix(text, detailsTime) && *section == "":
+		created, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(text[len(detailsTime):]))
+		if err != nil {
+			return false
+		}
+		r.Time = &created
+	case strings.HasPrefix(text, detailsGoroutine) && *section == "":
+		goroutine, err := strconv.ParseUint(strings.TrimSpace(text[len(detailsGoroutine):]), 10, 64)
+		if err != nil {
+			return false
+		}
+		r.Goroutine = goroutine
+	case strings.TrimSpace(text) == detailsData && *section == "":
+		*section = detailsData
+	case strings.TrimSpace(text) == detailsStack && *section != detailsStack:
+		*section = detailsStack
+	case *section == detailsData:
+		i := strings.Index(text, ": ")
+		if i <= 0 {
+			return false
+		}
+		if r.Data == nil {
+			r.Data = make(map[string]interface{})
+		}
+		r.Data[text[:i]] = strings.TrimSpace(text[i+2:])
+	case *section == detailsStack:
+		location, ok := ParseStackLocation(text)
+		if !ok {
+			return false
+		}
+		r.Stack = append(r.Stack, location)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *DetailsScanner) next() (string, bool) {
+	if s.held {
+		s.held = false
+		return s.pending, true
+	}
+
+	if !s.lines.Scan() {
+		s.err = s.lines.Err()
+		return "", false
+	}
+
+	return s.lines.Text(), true
+}
+
+func (s *DetailsScanner) hold(line string) {
+	s.pending = line
+	s.held = true
+}
+
+// stripPrefix removes the log prefix found in front of the [ERROR] line from
+// line. Prefixes which differ only in digits, such as timestamps, match.
+func stripPrefix(line, prefix string) (string, bool) {
+	if prefix == "" || strings.HasPrefix(line, prefix) {
+		return strings.TrimPrefix(line, prefix), true
+	}
+
+	if len(line) < len(prefix) || prefixShape(line[:len(prefix)]) != prefixShape(prefix) {
+		return "", false
+	}
+
+	return line[len(prefix):], true
+}
+
+func prefixShape(prefix string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return '0'
+		}
+		return r
+	}, prefix)
+}
@@ -0,0 +1,109 @@
+package xerrs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStackLocation(t *testing.T) {
+	cases := []struct {
+		line string
+		want StackLocation
+		ok   bool
+	}{
+		{"main.main [/app/main.go:12]", StackLocation{"main.main", "/app/main.go", 12}, true},
+		{"2024/01/02 15:04:05 main.main [/app/main.go:12]", StackLocation{"main.main", "/app/main.go", 12}, true},
+		{"example.com/pkg.(*T).Do[...] [C:/app/t.go:7]", StackLocation{"example.com/pkg.(*T).Do[...]", "C:/app/t.go", 7}, true},
+		{"main.main [/app/main.go:", StackLocation{}, false},
+		{"connection refused", StackLocation{}, false},
+	}
+
+	for _, c := range cases {
+		got, ok := ParseStackLocation(c.line)
+		if ok != c.ok || got != c.want {
+			t.Errorf("wrong location for %q: want=%v,%v got=%v,%v", c.line, c.want, c.ok, got, ok)
+		}
+	}
+}
+
+func TestParseDetails(t *testing.T) {
+	EnableTracking(true)
+	SetClock(func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC) })
+	defer func() {
+		EnableTracking(false)
+		SetClock(nil)
+	}()
+
+	err := Mask(New("connection refused"), fmt.Errorf("try again later"))
+	SetSeverity(err, SeverityWarning)
+	SetData(err, "host", "db1")
+	SetData(err, "attempt", 2)
+
+	got, e := ParseDetails(Details(err, 2))
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	created := Time(err)
+	want := Record{
+		Cause:     "connection refused",
+		Mask:      "try again later",
+		Severity:  SeverityWarning,
+		Time:      &created,
+		Goroutine: Goroutine(err),
+		Data:      map[string]interface{}{"attempt": "2", "host": "db1"},
+		Stack:     Stack(err)[:2],
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong record: want=%+v got=%+v", want, got)
+	}
+
+	if details := got.Details(2); details != Details(err, 2) {
+		t.Errorf("wrong details: want=%v got=%v", Details(err, 2), details)
+	}
+
+	if _, e := ParseDetails("connection refused"); e == nil {
+		t.Errorf("expected an error for text without Details")
+	}
+}
+
+func TestDetailsScanner(t *testing.T) {
+	log := strings.Join([]string{
+		"2024/01/02 15:04:05 starting",
+		"2024/01/02 15:04:06 [ERROR] first",
+		"2024/01/02 15:04:06 [STACK]:",
+		"2024/01/02 15:04:06 main.a [/app/a.go:1]",
+		"2024/01/02 15:04:07 main.b [/app/b.go:2]",
+		"2024/01/02 15:04:07 request done",
+		"",
+		"[ERROR] second",
+		"[DATA]:",
+		"id: 7",
+		"[STACK]:",
+		"main.c [/app/c.go:3]",
+		"main.d [/app/d",
+		"[ERROR] third",
+	}, "\n")
+
+	scanner := NewDetailsScanner(strings.NewReader(log))
+
+	var got []Record
+	for scanner.Scan() {
+		got = append(got, scanner.Record())
+	}
+	if e := scanner.Err(); e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	want := []Record{
+		{Cause: "first", Stack: []StackLocation{{"main.a", "/app/a.go", 1}, {"main.b", "/app/b.go", 2}}},
+		{Cause: "second", Data: map[string]interface{}{"id": "7"}, Stack: []StackLocation{{"main.c", "/app/c.go", 3}}},
+		{Cause: "third"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong records: want=%+v got=%+v", want, got)
+	}
+}
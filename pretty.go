@@ -0,0 +1,207 @@
+package xerrs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PrettyRenderer - a human friendly renderer for command line tools. It prints
+// the error with its mask, a tree of the error chain, the data of the whole
+// chain as an aligned table and the stack with the user code highlighted.
+// Details is not affected by it.
+type PrettyRenderer struct {
+	// Color controls ANSI colors. ColorAuto colors output written to a terminal
+	// unless NO_COLOR is set.
+	Color ColorMode
+	// Width is the maximum line width. Zero means $COLUMNS or 80.
+	Width int
+	// MaxStack is the number of stack locations printed. Zero means all of them.
+	MaxStack int
+}
+
+// Pretty - returns err rendered by a PrettyRenderer without colors
+// If err is nil then "" is returned
+func Pretty(err error) string {
+	var b strings.Builder
+	PrettyRenderer{Color: ColorNever}.Render(&b, err)
+
+	return b.String()
+}
+
+// Render - writes err to w
+func (r PrettyRenderer) Render(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	p := prettyPrinter{
+		color: colorizer(r.Color.enabled(w)),
+		width: r.width(),
+	}
+
+	p.line(0, p.color.paint("error: ", ansiBold, ansiRed)+p.fit(Scrub(Internal(err)), 7))
+	if mask := findMask(err); mask != nil {
+		p.line(0, p.color.paint("mask:  ", ansiBold, ansiYellow)+p.fit(Scrub(mask.Error()), 7))
+	}
+	if code := Code(err); code != "" {
+		p.line(0, p.color.paint("code:  ", ansiBold)+code)
+	}
+	if severity := SeverityOf(err); severity != SeverityUnset {
+		p.line(0, p.color.paint("level: ", ansiBold)+severity.String())
+	}
+
+	p.chain(err)
+	p.data(err)
+
+	stack := Stack(err)
+	if r.MaxStack > 0 && r.MaxStack < len(stack) {
+		stack = stack[:r.MaxStack]
+	}
+	p.stack(stack)
+
+	_, e := io.WriteString(w, p.String())
+	return e
+}
+
+func (r PrettyRenderer) width() int {
+	if r.Width > 0 {
+		return r.Width
+	}
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	return 80
+}
+
+type prettyPrinter struct {
+	strings.Builder
+	color colorizer
+	width int
+}
+
+func (p *prettyPrinter) line(indent int, text string) {
+	p.WriteString(strings.Repeat(" ", indent))
+	p.WriteString(text)
+	p.WriteByte('\n')
+}
+
+func (p *prettyPrinter) section(title string) {
+	p.WriteByte('\n')
+	p.line(0, p.color.paint(title, ansiBold, ansiBlue))
+}
+
+// fit truncates s so that it fits in the line after used columns
+func (p *prettyPrinter) fit(s string, used int) string {
+	s = strings.Join(strings.Fields(s), " ")
+
+	available := p.width - used
+	if available < 10 {
+		available = 10
+	}
+
+	if utf8.RuneCountInString(s) <= available {
+		return s
+	}
+
+	runes := []rune(s)
+	return string(runes[:available-1]) + "…"
+}
+
+// chain prints one line for every layer of the error chain which adds something
+func (p *prettyPrinter) chain(err error) {
+	var lines []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		x, ok := err.(*xerr)
+		if !ok {
+			text := p.color.paint(fmt.Sprintf("(%T)", err), ansiDim)
+			if errors.Unwrap(err) == nil {
+				text = Scrub(err.Error()) + " " + text
+			}
+			lines = append(lines, text)
+			continue
+		}
+
+		var parts []string
+		if x.msg != "" {
+			parts = append(parts, p.color.paint(Scrub(x.msg), ansiCyan))
+		}
+		if x.def != nil {
+			parts = append(parts, "["+x.def.Code+"]")
+		}
+		if x.mask != nil {
+			parts = append(parts, p.color.paint("masked as "+strconv.Quote(Scrub(x.mask.Error())), ansiYellow))
+		}
+		if len(parts) > 0 {
+			lines = append(lines, strings.Join(parts, " "))
+		}
+	}
+
+	if len(lines) < 2 {
+		return
+	}
+
+	p.section("chain:")
+	for i, text := range lines {
+		indent := 2 + 3*i
+		if i == 0 {
+			p.line(indent, text)
+			continue
+		}
+		p.line(indent-3, "└─ "+text)
+	}
+}
+
+// data prints the data of the whole chain as an aligned table
+func (p *prettyPrinter) data(err error) {
+	data := Data(err)
+	if len(data) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(data))
+	keyWidth := 0
+	for key := range data {
+		keys = append(keys, key)
+		if n := utf8.RuneCountInString(key); n > keyWidth {
+			keyWidth = n
+		}
+	}
+	sort.Strings(keys)
+
+	p.section("data:")
+	for _, key := range keys {
+		value := Scrub(fmt.Sprint(RedactValue(key, data[key])))
+		padded := key + strings.Repeat(" ", keyWidth-utf8.RuneCountInString(key))
+		p.line(2, p.color.paint(padded, ansiBold)+"  "+p.fit(value, 4+keyWidth))
+	}
+}
+
+// stack prints user code frames highlighted and standard library frames dimmed
+func (p *prettyPrinter) stack(stack []StackLocation) {
+	if len(stack) == 0 {
+		return
+	}
+
+	p.section("stack:")
+	for _, location := range stack {
+		function := p.fit(location.Function, 2)
+		file := p.fit(fmt.Sprintf("%s:%d", location.File, location.Line), 6)
+
+		if isStandardLibrary(location.Function) {
+			p.line(2, p.color.paint(function, ansiDim))
+			p.line(6, p.color.paint(file, ansiDim))
+			continue
+		}
+
+		p.line(2, p.color.paint(function, ansiBold))
+		p.line(6, file)
+	}
+}
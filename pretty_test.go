@@ -0,0 +1,81 @@
+package xerrs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPretty(t *testing.T) {
+	cause := Extend(fmt.Errorf("connection refused"))
+	SetData(cause, "host", "db1")
+	SetData(cause, "attempt", 2)
+
+	err := Mask(Wrap(cause, "load shipment"), fmt.Errorf("try again later"))
+	SetData(err, "attempt", 3)
+
+	got := Pretty(err)
+	for _, want := range []string{
+		"error: load shipment: connection refused\n",
+		"mask:  try again later\n",
+		"chain:\n  load shipment masked as \"try again later\"\n  └─ connection refused (*errors.errorString)\n",
+		"data:\n  attempt  3\n  host     db1\n",
+		"stack:\n  github.com/RoseRocket/xerrs.TestPretty\n      ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got=%v", want, got)
+		}
+	}
+
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("expected no colors, got=%q", got)
+	}
+
+	if got := Pretty(nil); got != "" {
+		t.Errorf("wrong output for nil: want=%q got=%q", "", got)
+	}
+}
+
+func TestPrettyRenderer(t *testing.T) {
+	err := &xerr{
+		cause: fmt.Errorf("%s", strings.Repeat("x", 100)),
+		stack: []StackLocation{
+			{Function: "example.com/app.main", File: "/app/main.go", Line: 10},
+			{Function: "runtime.main", File: "/go/src/runtime/proc.go", Line: 250},
+		},
+	}
+
+	var buf bytes.Buffer
+	if e := (PrettyRenderer{Width: 40, Color: ColorAlways}).Render(&buf, err); e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	got := buf.String()
+	for _, want := range []string{
+		ansiBold + "example.com/app.main" + ansiReset,
+		ansiDim + "runtime.main" + ansiReset,
+		"error: " + ansiReset + strings.Repeat("x", 32) + "…\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got=%q", want, got)
+		}
+	}
+
+	buf.Reset()
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("COLUMNS", "20")
+	(PrettyRenderer{}).Render(&buf, err)
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("expected no colors, got=%q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "error: xxxxxxxxxxxx…\n") {
+		t.Errorf("expected $COLUMNS to limit the width, got=%q", buf.String())
+	}
+
+	buf.Reset()
+	(PrettyRenderer{MaxStack: 1}).Render(&buf, err)
+	if strings.Contains(buf.String(), "runtime.main") {
+		t.Errorf("expected only one stack location, got=%q", buf.String())
+	}
+}
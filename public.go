@@ -0,0 +1,121 @@
+package xerrs
+
+import "strings"
+
+// DefaultPublicMessage - returned by Public when no part of the error is safe to expose
+var DefaultPublicMessage = "internal error"
+
+// MarkPublic - creates a new xerr which marks the message of err as safe to
+// expose to clients. err itself is not modified, so shared sentinel errors can
+// be marked too.
+// If err is nil then nil is returned
+// It will also set the stack.
+func MarkPublic(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return build(&xerr{
+		cause:  err,
+		public: true,
+	}, nil)
+}
+
+// Public - returns the message which is safe to show to clients: the outermost
+// mask, or the message of the outermost error marked with MarkPublic.
+// The message of a public Wrap or Wrapf error is its own annotation only.
+// If nothing in the chain is public then DefaultPublicMessage is returned
+// If err is nil then "" is returned
+func Public(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	for err != nil {
+		x, ok := err.(*xerr)
+		if !ok {
+			break
+		}
+
+		if x.mask != nil {
+			return x.mask.Error()
+		}
+
+		if x.public {
+			return publicText(x)
+		}
+
+		err = x.cause
+	}
+
+	return DefaultPublicMessage
+}
+
+// publicText returns the mask or own message of the outermost layer of err
+// which has one
+func publicText(err error) string {
+	for {
+		x, ok := err.(*xerr)
+		if !ok {
+			return err.Error()
+		}
+
+		if x.mask != nil {
+			return x.mask.Error()
+		}
+
+		if x.msg != "" {
+			return x.msg
+		}
+
+		err = x.cause
+	}
+}
+
+// Internal - returns the full message of err with every Wrap annotation and
+// the original cause, ignoring masks. Use it for logging.
+// If err is not xerr then err.Error() is returned, with the messages of the
+// errors it wraps replaced by their internal messages, e.g. for masked errors
+// wrapped with fmt.Errorf and %w
+// If err is nil then "" is returned
+func Internal(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	x, ok := err.(*xerr)
+	if !ok {
+		return unmaskedMessage(err)
+	}
+
+	if x.msg != "" {
+		return x.msg + ": " + Internal(x.cause)
+	}
+
+	return Internal(x.cause)
+}
+
+// unmaskedMessage returns the message of err, which is not xerr, with the
+// messages of the errors it wraps replaced by their internal messages
+func unmaskedMessage(err error) string {
+	var inner []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		inner = []error{e.Unwrap()}
+	case interface{ Unwrap() []error }:
+		inner = e.Unwrap()
+	}
+
+	message := err.Error()
+	for _, e := range inner {
+		if e == nil {
+			continue
+		}
+
+		if internal := Internal(e); internal != e.Error() {
+			message = strings.Replace(message, e.Error(), internal, 1)
+		}
+	}
+
+	return message
+}
@@ -0,0 +1,103 @@
+package xerrs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPublicInternal(t *testing.T) {
+	cause := errors.New("pq: connection refused")
+
+	for _, test := range []struct {
+		description  string
+		in           error
+		wantPublic   string
+		wantInternal string
+	}{
+		{
+			description:  "nil error",
+			in:           nil,
+			wantPublic:   "",
+			wantInternal: "",
+		},
+		{
+			description:  "basic error",
+			in:           cause,
+			wantPublic:   DefaultPublicMessage,
+			wantInternal: "pq: connection refused",
+		},
+		{
+			description:  "wrapped error",
+			in:           Wrap(cause, "load shipment"),
+			wantPublic:   DefaultPublicMessage,
+			wantInternal: "load shipment: pq: connection refused",
+		},
+		{
+			description:  "wrapped mask",
+			in:           Wrap(Mask(cause, errors.New("try again later")), "load shipment"),
+			wantPublic:   "try again later",
+			wantInternal: "load shipment: pq: connection refused",
+		},
+		{
+			description:  "masked error wrapped with %w",
+			in:           fmt.Errorf("handler: %w", Mask(New("db password wrong"), errors.New("try later"))),
+			wantPublic:   DefaultPublicMessage,
+			wantInternal: "handler: db password wrong",
+		},
+		{
+			description:  "joined masked errors",
+			in:           errors.Join(Mask(cause, errors.New("try later")), errors.New("timeout")),
+			wantPublic:   DefaultPublicMessage,
+			wantInternal: "pq: connection refused\ntimeout",
+		},
+		{
+			description:  "public error",
+			in:           Wrap(MarkPublic(New("shipment not found")), "load shipment"),
+			wantPublic:   "shipment not found",
+			wantInternal: "load shipment: shipment not found",
+		},
+		{
+			description:  "public basic error",
+			in:           MarkPublic(errors.New("shipment not found")),
+			wantPublic:   "shipment not found",
+			wantInternal: "shipment not found",
+		},
+		{
+			description:  "public wrap hides its cause",
+			in:           MarkPublic(Wrap(cause, "shipment is unavailable")),
+			wantPublic:   "shipment is unavailable",
+			wantInternal: "shipment is unavailable: pq: connection refused",
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			if got := Public(test.in); got != test.wantPublic {
+				t.Errorf("wrong public message: want=%v got=%v", test.wantPublic, got)
+			}
+			if got := Internal(test.in); got != test.wantInternal {
+				t.Errorf("wrong internal message: want=%v got=%v", test.wantInternal, got)
+			}
+		})
+	}
+
+	if MarkPublic(nil) != nil {
+		t.Error("expected nil error")
+	}
+
+	sentinel := New("shipment not found")
+	if got := Public(Wrap(MarkPublic(sentinel), "load shipment")); got != "shipment not found" {
+		t.Errorf("wrong public message: want=%v got=%v", "shipment not found", got)
+	}
+	if got := Public(sentinel); got != DefaultPublicMessage {
+		t.Errorf("expected sentinel to stay private: want=%v got=%v", DefaultPublicMessage, got)
+	}
+}
+
+func TestDetailsKeepsNestedCause(t *testing.T) {
+	err := Wrap(Mask(errors.New("ERROR"), errors.New("MASK")), "wrap")
+
+	if got := Details(err, 0); !strings.HasPrefix(got, "\n[ERROR] ERROR\n") {
+		t.Errorf("wrong output prefix: wanted prefix=%v got=%v", "\n[ERROR] ERROR\n", got)
+	}
+}
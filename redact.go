@@ -0,0 +1,255 @@
+package xerrs
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// RedactedText - replaces sensitive values in Details, JSON and slog output
+const RedactedText = "[REDACTED]"
+
+// Redacted - wraps a value which must never be printed.
+// Formatting, JSON and slog output always render RedactedText, the raw value
+// is only available through Value.
+type Redacted[T any] struct {
+	value T
+}
+
+// Redact - wraps v so it is rendered as RedactedText
+func Redact[T any](v T) Redacted[T] {
+	return Redacted[T]{value: v}
+}
+
+// Value - returns the raw wrapped value
+func (r Redacted[T]) Value() T {
+	return r.value
+}
+
+// Returns RedactedText
+func (r Redacted[T]) String() string {
+	return RedactedText
+}
+
+// Format - implements fmt.Formatter so that no verb prints the raw value
+func (r Redacted[T]) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, RedactedText)
+}
+
+// MarshalJSON - encodes RedactedText
+func (r Redacted[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedText)
+}
+
+// LogValue - implements slog.LogValuer and returns RedactedText
+func (r Redacted[T]) LogValue() slog.Value {
+	return slog.StringValue(RedactedText)
+}
+
+// Key - a typed data key. Values are stored with SetData under the key name.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey - creates a typed data key
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+// NewSensitiveKey - creates a typed data key and marks its name as sensitive
+func NewSensitiveKey[T any](name string) Key[T] {
+	MarkSensitive(name)
+	return Key[T]{name: name}
+}
+
+// Name - returns the data key name
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// Set - sets custom data stored in xerr
+// If err is not xerr then nothing happens
+func (k Key[T]) Set(err error, value T) {
+	SetData(err, k.name, value)
+}
+
+// Get - returns custom data stored in xerr, see GetData
+// If the value is not a T then (zero, false) is returned
+func (k Key[T]) Get(err error) (T, bool) {
+	v, ok := GetData(err, k.name)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	value, ok := v.(T)
+	return value, ok
+}
+
+// Scrubber - removes sensitive parts from a message
+type Scrubber interface {
+	Scrub(s string) string
+}
+
+// ScrubberFunc - adapts a function to Scrubber
+type ScrubberFunc func(s string) string
+
+// Scrub - calls f(s)
+func (f ScrubberFunc) Scrub(s string) string {
+	return f(s)
+}
+
+// RegexpScrubber - replaces every match of Pattern with Replacement
+type RegexpScrubber struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// Scrub - replaces every match of Pattern with Replacement
+func (scrubber RegexpScrubber) Scrub(s string) string {
+	return scrubber.Pattern.ReplaceAllString(s, scrubber.Replacement)
+}
+
+var (
+	// EmailScrubber - redacts email addresses
+	EmailScrubber Scrubber = RegexpScrubber{
+		Pattern:     regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		Replacement: RedactedText,
+	}
+
+	// JWTScrubber - redacts JSON Web Tokens
+	JWTScrubber Scrubber = RegexpScrubber{
+		Pattern:     regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+		Replacement: RedactedText,
+	}
+
+	// CreditCardScrubber - redacts card numbers of 13 to 19 digits which pass the Luhn check
+	CreditCardScrubber Scrubber = ScrubberFunc(func(s string) string {
+		return creditCardPattern.ReplaceAllStringFunc(s, func(match string) string {
+			if !luhn(match) {
+				return match
+			}
+			return RedactedText
+		})
+	})
+
+	creditCardPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+)
+
+var redaction = struct {
+	sync.RWMutex
+	keys      map[string]bool
+	scrubbers []Scrubber
+}{
+	keys: make(map[string]bool),
+}
+
+// MarkSensitive - marks data keys whose values are rendered as RedactedText
+func MarkSensitive(keys ...string) {
+	redaction.Lock()
+	defer redaction.Unlock()
+
+	for _, key := range keys {
+		redaction.keys[key] = true
+	}
+}
+
+// IsSensitive - returns true if key was marked with MarkSensitive
+func IsSensitive(key string) bool {
+	redaction.RLock()
+	defer redaction.RUnlock()
+
+	return redaction.keys[key]
+}
+
+// RegisterScrubber - adds scrubbers which are applied to messages and data
+// values when rendering Details, JSON or slog output, e.g.
+//
+//	xerrs.RegisterScrubber(xerrs.EmailScrubber, xerrs.JWTScrubber, xerrs.CreditCardScrubber)
+func RegisterScrubber(scrubbers ...Scrubber) {
+	redaction.Lock()
+	defer redaction.Unlock()
+
+	redaction.scrubbers = append(redaction.scrubbers, scrubbers...)
+}
+
+// ResetRedaction - removes every sensitive key and scrubber
+func ResetRedaction() {
+	redaction.Lock()
+	defer redaction.Unlock()
+
+	redaction.keys = make(map[string]bool)
+	redaction.scrubbers = nil
+}
+
+// Scrub - applies every registered scrubber to s
+func Scrub(s string) string {
+	redaction.RLock()
+	defer redaction.RUnlock()
+
+	for _, scrubber := range redaction.scrubbers {
+		s = scrubber.Scrub(s)
+	}
+
+	return s
+}
+
+// RedactValue - returns the value stored at key as it should be rendered:
+// RedactedText for sensitive keys, the value itself for booleans and numbers,
+// and the scrubbed text of any other value, e.g. strings, errors, slices and
+// structs. xerr values are returned as they are because their output is
+// already scrubbed.
+// key can be empty for values which are not stored in data, e.g. Errorf args
+func RedactValue(key string, value interface{}) interface{} {
+	if key != "" && IsSensitive(key) {
+		return RedactedText
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return Scrub(v)
+	case *xerr:
+		return v
+	case error:
+		return Scrub(v.Error())
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return value
+	}
+
+	return Scrub(fmt.Sprint(value))
+}
+
+// luhn reports whether the digits in number pass the Luhn checksum
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,343 @@
+package xerrs
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReporterOptions - controls which reported errors a Reporter delivers to its sinks.
+// Zero values fall back to the defaults documented on each field.
+type ReporterOptions struct {
+	// QueueSize is the number of reports waiting for delivery. Reports made while
+	// the queue is full are dropped. Defaults to 1024.
+	QueueSize int
+	// RateLimit is the number of reports delivered per fingerprint in every
+	// RateInterval. Zero means no limit.
+	RateLimit int
+	// RateInterval is the window of RateLimit. Defaults to one minute.
+	RateInterval time.Duration
+	// SampleRates is the probability in [0, 1] of delivering a report of the
+	// given severity. Severities which are not in the map are always delivered.
+	SampleRates map[Severity]float64
+	// DedupWindow drops reports with the fingerprint and message of a report
+	// delivered less than DedupWindow ago. Zero disables deduplication.
+	DedupWindow time.Duration
+	// OnError is called with errors returned by sinks. Defaults to ignoring them.
+	OnError func(error)
+	// Clock returns the current time. Defaults to time.Now.
+	Clock func() time.Time
+	// Rand returns numbers in [0, 1) for sampling. Defaults to math/rand.
+	Rand func() float64
+}
+
+// ReporterStats - counters of a Reporter
+type ReporterStats struct {
+	// Reported is the number of calls to Report with a non nil error.
+	Reported uint64
+	// Delivered is the number of reports sent to the sinks.
+	Delivered uint64
+	// Failed is the number of reports which at least one sink returned an error for.
+	Failed uint64
+	// Dropped is the number of reports lost because the queue was full or the
+	// reporter was closed.
+	Dropped uint64
+	// RateLimited, Sampled and Deduplicated are the reports skipped on purpose.
+	RateLimited  uint64
+	Sampled      uint64
+	Deduplicated uint64
+}
+
+// Reporter - delivers reported errors to sinks in the background. Create it
+// with NewReporter and stop it with Close.
+type Reporter struct {
+	opts  ReporterOptions
+	sinks []Sink
+
+	queue   chan report
+	closing chan struct{}
+	done    chan struct{}
+
+	// mu guards closed so that no report is queued after run drained the queue.
+	// It is never held while blocking.
+	mu     sync.RWMutex
+	closed bool
+
+	// limitMu guards the state of sampling, deduplication and rate limiting
+	limitMu sync.Mutex
+	limits  map[string]*rateWindow
+	seen    map[string]time.Time
+	pruned  time.Time
+
+	counter struct {
+		reported, delivered, failed, dropped, rateLimited, sampled, deduplicated atomic.Uint64
+	}
+}
+
+type report struct {
+	ctx     context.Context
+	err     error
+	flushed chan struct{}
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewReporter - returns a Reporter which delivers errors to sinks and starts
+// its background goroutine
+func NewReporter(opts ReporterOptions, sinks ...Sink) *Reporter {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.RateInterval <= 0 {
+		opts.RateInterval = time.Minute
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	if opts.Clock == nil {
+		opts.Clock = time.Now
+	}
+	if opts.Rand == nil {
+		opts.Rand = rand.Float64
+	}
+
+	r := &Reporter{
+		opts:    opts,
+		sinks:   sinks,
+		queue:   make(chan report, opts.QueueSize),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+		limits:  make(map[string]*rateWindow),
+		seen:    make(map[string]time.Time),
+	}
+
+	go r.run()
+
+	return r
+}
+
+// Report - queues err for delivery to the sinks without waiting for it
+// Sinks receive a context with the values of ctx which is never canceled
+// If err is nil then nothing happens
+func (r *Reporter) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	r.counter.reported.Add(1)
+
+	if !r.admit(err) {
+		return
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		r.counter.dropped.Add(1)
+		return
+	}
+
+	select {
+	case r.queue <- report{ctx: context.WithoutCancel(ctx), err: err}:
+	default:
+		r.counter.dropped.Add(1)
+	}
+}
+
+// Flush - waits until every error reported before the call was delivered
+// If ctx is done first then its error is returned
+func (r *Reporter) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+
+	select {
+	case r.queue <- report{flushed: flushed}:
+	case <-r.closing:
+		return r.wait(ctx, r.done)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// a closed reporter may stop before it reaches the flush
+	select {
+	case <-flushed:
+		return nil
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close - stops accepting reports and waits until the queued ones were delivered
+// If ctx is done first then its error is returned and delivery continues in the
+// background
+func (r *Reporter) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.closing)
+	}
+	r.mu.Unlock()
+
+	return r.wait(ctx, r.done)
+}
+
+// Stats - returns the counters of the reporter
+func (r *Reporter) Stats() ReporterStats {
+	return ReporterStats{
+		Reported:     r.counter.reported.Load(),
+		Delivered:    r.counter.delivered.Load(),
+		Failed:       r.counter.failed.Load(),
+		Dropped:      r.counter.dropped.Load(),
+		RateLimited:  r.counter.rateLimited.Load(),
+		Sampled:      r.counter.sampled.Load(),
+		Deduplicated: r.counter.deduplicated.Load(),
+	}
+}
+
+func (r *Reporter) wait(ctx context.Context, done chan struct{}) error {
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Reporter) run() {
+	defer close(r.done)
+
+	for {
+		select {
+		case item := <-r.queue:
+			r.deliver(item)
+		case <-r.closing:
+			// Report queues nothing once closing is closed, so the queue only
+			// holds the last reports and flushes
+			for {
+				select {
+				case item := <-r.queue:
+					r.deliver(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *Reporter) deliver(item report) {
+	if item.flushed != nil {
+		close(item.flushed)
+		return
+	}
+
+	failed := false
+	for _, sink := range r.sinks {
+		if err := sink.Send(item.ctx, item.err); err != nil {
+			failed = true
+			r.opts.OnError(err)
+		}
+	}
+
+	r.counter.delivered.Add(1)
+	if failed {
+		r.counter.failed.Add(1)
+	}
+}
+
+// admit applies sampling, deduplication and rate limiting to err
+func (r *Reporter) admit(err error) bool {
+	if rate, ok := r.opts.SampleRates[SeverityOf(err)]; ok {
+		r.limitMu.Lock()
+		n := r.opts.Rand()
+		r.limitMu.Unlock()
+
+		if n >= rate {
+			r.counter.sampled.Add(1)
+			return false
+		}
+	}
+
+	if r.opts.RateLimit <= 0 && r.opts.DedupWindow <= 0 {
+		return true
+	}
+
+	fingerprint := Fingerprint(err)
+	now := r.opts.Clock()
+
+	r.limitMu.Lock()
+	defer r.limitMu.Unlock()
+
+	r.prune(now)
+
+	key := fingerprint + "\n" + Internal(err)
+	if r.opts.DedupWindow > 0 {
+		if last, ok := r.seen[key]; ok && now.Sub(last) < r.opts.DedupWindow {
+			r.counter.deduplicated.Add(1)
+			return false
+		}
+	}
+
+	if r.opts.RateLimit > 0 {
+		window, ok := r.limits[fingerprint]
+		if !ok || now.Sub(window.start) >= r.opts.RateInterval {
+			window = &rateWindow{start: now}
+			r.limits[fingerprint] = window
+		}
+
+		if window.count >= r.opts.RateLimit {
+			r.counter.rateLimited.Add(1)
+			return false
+		}
+		window.count++
+	}
+
+	if r.opts.DedupWindow > 0 {
+		r.seen[key] = now
+	}
+
+	return true
+}
+
+// prune forgets expired rate windows and deduplication entries, at most once
+// per RateInterval
+func (r *Reporter) prune(now time.Time) {
+	if now.Sub(r.pruned) < r.opts.RateInterval {
+		return
+	}
+	r.pruned = now
+
+	for fingerprint, window := range r.limits {
+		if now.Sub(window.start) >= r.opts.RateInterval {
+			delete(r.limits, fingerprint)
+		}
+	}
+
+	for key, last := range r.seen {
+		if now.Sub(last) >= r.opts.DedupWindow {
+			delete(r.seen, key)
+		}
+	}
+}
+
+var defaultReporter atomic.Pointer[Reporter]
+
+// SetReporter - sets the Reporter used by Report
+// If r is nil then Report does nothing
+func SetReporter(r *Reporter) {
+	defaultReporter.Store(r)
+}
+
+// Report - reports err with the Reporter set by SetReporter
+// If no reporter is set or err is nil then nothing happens
+func Report(ctx context.Context, err error) {
+	if r := defaultReporter.Load(); r != nil {
+		r.Report(ctx, err)
+	}
+}
@@ -0,0 +1,254 @@
+package xerrs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReporter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deliver", func(t *testing.T) {
+		sink := &MemorySink{}
+		r := NewReporter(ReporterOptions{}, sink)
+		defer r.Close(ctx)
+
+		err := New("ABC")
+		r.Report(ctx, err)
+		r.Report(ctx, nil)
+
+		if e := r.Flush(ctx); e != nil {
+			t.Fatalf("unexpected error: %v", e)
+		}
+
+		if got := sink.Errors(); len(got) != 1 || got[0] != err {
+			t.Errorf("wrong errors: want=%v got=%v", []error{err}, got)
+		}
+
+		want := ReporterStats{Reported: 1, Delivered: 1}
+		if got := r.Stats(); got != want {
+			t.Errorf("wrong stats: want=%+v got=%+v", want, got)
+		}
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		started := make(chan struct{}, 3)
+		release := make(chan struct{})
+		sink := SinkFunc(func(ctx context.Context, err error) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		})
+
+		r := NewReporter(ReporterOptions{QueueSize: 1}, sink)
+		r.Report(ctx, New("first"))
+		<-started
+		r.Report(ctx, New("second"))
+		r.Report(ctx, New("third"))
+
+		close(release)
+		r.Close(ctx)
+
+		want := ReporterStats{Reported: 3, Delivered: 2, Dropped: 1}
+		if got := r.Stats(); got != want {
+			t.Errorf("wrong stats: want=%+v got=%+v", want, got)
+		}
+	})
+
+	t.Run("report while flush and close wait", func(t *testing.T) {
+		started := make(chan struct{}, 3)
+		release := make(chan struct{})
+		sink := SinkFunc(func(ctx context.Context, err error) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		})
+
+		r := NewReporter(ReporterOptions{QueueSize: 1}, sink)
+		r.Report(ctx, New("first"))
+		<-started
+		r.Report(ctx, New("second"))
+
+		flushed := make(chan error, 1)
+		go func() { flushed <- r.Flush(ctx) }()
+		time.Sleep(10 * time.Millisecond)
+
+		closed := make(chan error, 1)
+		go func() { closed <- r.Close(ctx) }()
+		time.Sleep(10 * time.Millisecond)
+
+		reported := make(chan struct{})
+		go func() {
+			r.Report(ctx, New("third"))
+			close(reported)
+		}()
+
+		select {
+		case <-reported:
+		case <-time.After(time.Second):
+			t.Errorf("Report blocked while the queue was full")
+		}
+
+		close(release)
+		if err := <-flushed; err != nil {
+			t.Errorf("unexpected flush error: %v", err)
+		}
+		if err := <-closed; err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	})
+
+	t.Run("sink error", func(t *testing.T) {
+		var failures []error
+		failure := errors.New("sink down")
+		sink := SinkFunc(func(ctx context.Context, err error) error { return failure })
+
+		r := NewReporter(ReporterOptions{OnError: func(err error) { failures = append(failures, err) }}, sink)
+		r.Report(ctx, New("ABC"))
+		r.Close(ctx)
+
+		if len(failures) != 1 || failures[0] != failure {
+			t.Errorf("wrong sink errors: want=%v got=%v", []error{failure}, failures)
+		}
+		if got := r.Stats().Failed; got != 1 {
+			t.Errorf("wrong failed count: want=%v got=%v", 1, got)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		sink := &MemorySink{}
+		r := NewReporter(ReporterOptions{}, sink)
+		r.Close(ctx)
+		r.Report(ctx, New("ABC"))
+
+		if e := r.Flush(ctx); e != nil {
+			t.Errorf("unexpected error: %v", e)
+		}
+		if e := r.Close(ctx); e != nil {
+			t.Errorf("unexpected error: %v", e)
+		}
+		if got := r.Stats().Dropped; got != 1 {
+			t.Errorf("wrong dropped count: want=%v got=%v", 1, got)
+		}
+	})
+
+	t.Run("flush timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		sink := SinkFunc(func(ctx context.Context, err error) error {
+			<-release
+			return nil
+		})
+
+		r := NewReporter(ReporterOptions{}, sink)
+		defer func() {
+			close(release)
+			r.Close(ctx)
+		}()
+		r.Report(ctx, New("ABC"))
+
+		timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		if e := r.Flush(timeout); e != context.DeadlineExceeded {
+			t.Errorf("wrong error: want=%v got=%v", context.DeadlineExceeded, e)
+		}
+	})
+}
+
+func TestReporterFilters(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	newErr := func(message string, severity Severity) error {
+		err := New(message)
+		SetSeverity(err, severity)
+		return err
+	}
+
+	cases := []struct {
+		name string
+		opts ReporterOptions
+		// steps are reported in order, advancing the clock by the given duration first
+		steps     []time.Duration
+		severity  Severity
+		messages  []string
+		delivered int
+		want      ReporterStats
+	}{
+		{
+			name:      "rate limit",
+			opts:      ReporterOptions{RateLimit: 2, RateInterval: time.Minute},
+			steps:     []time.Duration{0, 0, 0, time.Minute},
+			messages:  []string{"a", "a", "a", "a"},
+			delivered: 3,
+			want:      ReporterStats{Reported: 4, Delivered: 3, RateLimited: 1},
+		},
+		{
+			name:      "dedup",
+			opts:      ReporterOptions{DedupWindow: time.Second},
+			steps:     []time.Duration{0, 0, 0, time.Second},
+			messages:  []string{"a", "a", "b", "a"},
+			delivered: 3,
+			want:      ReporterStats{Reported: 4, Delivered: 3, Deduplicated: 1},
+		},
+		{
+			name:      "sampled out",
+			opts:      ReporterOptions{SampleRates: map[Severity]float64{SeverityDebug: 0.1}},
+			severity:  SeverityDebug,
+			steps:     []time.Duration{0, 0},
+			messages:  []string{"a", "b"},
+			delivered: 0,
+			want:      ReporterStats{Reported: 2, Sampled: 2},
+		},
+		{
+			name:      "sampled in",
+			opts:      ReporterOptions{SampleRates: map[Severity]float64{SeverityDebug: 0.1, SeverityError: 0.9}},
+			severity:  SeverityError,
+			steps:     []time.Duration{0, 0},
+			messages:  []string{"a", "b"},
+			delivered: 2,
+			want:      ReporterStats{Reported: 2, Delivered: 2},
+		},
+	}
+
+	for _, c := range cases {
+		sink := &MemorySink{}
+		c.opts.Clock = clock
+		c.opts.Rand = func() float64 { return 0.5 }
+		r := NewReporter(c.opts, sink)
+
+		for i, step := range c.steps {
+			now = now.Add(step)
+			// errors created on one line share their fingerprint
+			err := newErr(c.messages[i], c.severity)
+			r.Report(ctx, err)
+		}
+		r.Close(ctx)
+
+		if got := len(sink.Errors()); got != c.delivered {
+			t.Errorf("wrong number of delivered errors for %s: want=%v got=%v", c.name, c.delivered, got)
+		}
+		if got := r.Stats(); got != c.want {
+			t.Errorf("wrong stats for %s: want=%+v got=%+v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	ctx := context.Background()
+	Report(ctx, New("no reporter"))
+
+	sink := &MemorySink{}
+	r := NewReporter(ReporterOptions{}, sink)
+	SetReporter(r)
+	defer SetReporter(nil)
+
+	Report(ctx, New("ABC"))
+	r.Close(ctx)
+
+	if got := len(sink.Errors()); got != 1 {
+		t.Errorf("wrong number of delivered errors: want=%v got=%v", 1, got)
+	}
+}
@@ -0,0 +1,271 @@
+// Package retry runs operations with exponential backoff, using xerrs error
+// data to decide when to stop and how long to wait.
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+const (
+	// PermanentKey - xerrs data key which marks an error as permanent
+	PermanentKey = "retry.permanent"
+
+	// RetryAfterKey - xerrs data key which holds a time.Duration hint for the next attempt
+	RetryAfterKey = "retry.after"
+
+	// AttemptsKey - xerrs data key under which Do stores the Attempts history
+	AttemptsKey = "retry.attempts"
+)
+
+// Clock - source of time used by Do. Tests can supply a fake one.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Policy - describes how Do retries an operation.
+// Zero values fall back to the defaults documented on each field.
+type Policy struct {
+	// InitialInterval is the delay after the first failed attempt. Defaults to 100ms.
+	InitialInterval time.Duration
+	// Multiplier grows the delay after every attempt. Defaults to 2.
+	Multiplier float64
+	// MaxInterval caps a single delay. Zero means no cap.
+	MaxInterval time.Duration
+	// Jitter randomizes every delay by +/- Jitter*delay. Values above 1 are treated as 1.
+	Jitter float64
+	// MaxAttempts limits the number of calls to the operation. Zero means no limit.
+	MaxAttempts int
+	// MaxElapsedTime stops retrying once the next attempt would start after it.
+	// Zero means no limit.
+	MaxElapsedTime time.Duration
+	// Clock is used to measure time and to wait between attempts. Defaults to the real clock.
+	Clock Clock
+	// Rand returns numbers in [0, 1) for the jitter. Defaults to math/rand.
+	Rand func() float64
+}
+
+// Attempt - one call of the operation made by Do
+type Attempt struct {
+	Number   int           `json:"number"`
+	Err      error         `json:"error"`
+	Start    time.Duration `json:"start"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Returns a string which represents Attempt. Used for logging.
+func (a Attempt) String() string {
+	return fmt.Sprintf("#%d at +%s (took %s): %v", a.Number, a.Start, a.Duration, a.Err)
+}
+
+// MarshalJSON - encodes Attempt with the message of its error, which would
+// otherwise be encoded as an empty object
+func (a Attempt) MarshalJSON() ([]byte, error) {
+	type attempt Attempt
+
+	out := struct {
+		attempt
+		Err string `json:"error,omitempty"`
+	}{attempt: attempt(a)}
+	if a.Err != nil {
+		out.Err = a.Err.Error()
+	}
+
+	return json.Marshal(out)
+}
+
+// Attempts - history of every attempt made by Do
+type Attempts []Attempt
+
+// Returns a string which represents Attempts. Used for logging.
+func (attempts Attempts) String() string {
+	lines := make([]string, len(attempts))
+	for i, a := range attempts {
+		lines[i] = a.String()
+	}
+
+	return strings.Join(lines, "; ")
+}
+
+// Permanent - marks err as permanent so that Do stops retrying it.
+// If err is nil then nil is returned.
+// The original error is extended, so shared sentinel errors are never modified.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	err = xerrs.Extend(err)
+	xerrs.SetData(err, PermanentKey, true)
+
+	return err
+}
+
+// IsPermanent - returns true if err or one of the errors it wraps was marked with Permanent
+func IsPermanent(err error) bool {
+	v, ok := chainData(err, PermanentKey)
+	if !ok {
+		return false
+	}
+
+	permanent, _ := v.(bool)
+	return permanent
+}
+
+// WithRetryAfter - attaches a hint telling Do to wait d before the next attempt.
+// If err is nil then nil is returned.
+// The original error is extended, so shared sentinel errors are never modified.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	err = xerrs.Extend(err)
+	xerrs.SetData(err, RetryAfterKey, d)
+
+	return err
+}
+
+// RetryAfter - returns the hint attached with WithRetryAfter
+func RetryAfter(err error) (time.Duration, bool) {
+	v, ok := chainData(err, RetryAfterKey)
+	if !ok {
+		return 0, false
+	}
+
+	d, ok := v.(time.Duration)
+	return d, ok
+}
+
+// Do - calls fn until it succeeds, returns a permanent error, ctx is done or
+// the policy limits are reached.
+// On failure the returned error wraps the last error and stores the Attempts
+// history at AttemptsKey, so Details prints every attempt.
+func Do(ctx context.Context, fn func(ctx context.Context) error, policy Policy) error {
+	clock := policy.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	start := clock.Now()
+	interval := policy.initialInterval()
+
+	var attempts Attempts
+	for n := 1; ; n++ {
+		attemptStart := clock.Now()
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		attempts = append(attempts, Attempt{
+			Number:   n,
+			Err:      err,
+			Start:    attemptStart.Sub(start),
+			Duration: clock.Now().Sub(attemptStart),
+		})
+
+		if IsPermanent(err) {
+			return giveUp(err, attempts, "permanent error")
+		}
+
+		if policy.MaxAttempts > 0 && n >= policy.MaxAttempts {
+			return giveUp(err, attempts, "max attempts reached")
+		}
+
+		delay, ok := RetryAfter(err)
+		if !ok {
+			delay = policy.jitter(interval)
+		}
+		interval = policy.next(interval)
+
+		if policy.MaxElapsedTime > 0 && clock.Now().Add(delay).Sub(start) > policy.MaxElapsedTime {
+			return giveUp(err, attempts, "max elapsed time reached")
+		}
+
+		select {
+		case <-ctx.Done():
+			return giveUp(ctx.Err(), attempts, "context done")
+		case <-clock.After(delay):
+		}
+	}
+}
+
+// giveUp wraps err with the stack of the caller of Do
+func giveUp(err error, attempts Attempts, reason string) error {
+	return xerrs.Wrapf(err, "retry: %s after %d attempt(s)", reason, len(attempts),
+		xerrs.WithData(AttemptsKey, attempts), xerrs.WithSkip(2))
+}
+
+// chainData returns the data stored at name in err or the errors it wraps,
+// including errors which are wrapped by errors that are not xerr
+func chainData(err error, name string) (interface{}, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if v, ok := xerrs.GetData(err, name); ok {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+func (policy Policy) initialInterval() time.Duration {
+	if policy.InitialInterval <= 0 {
+		return 100 * time.Millisecond
+	}
+
+	return policy.InitialInterval
+}
+
+// next returns the interval which follows current
+func (policy Policy) next(current time.Duration) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	next := time.Duration(float64(current) * multiplier)
+	if policy.MaxInterval > 0 && next > policy.MaxInterval {
+		next = policy.MaxInterval
+	}
+
+	return next
+}
+
+// jitter randomizes d by +/- policy.Jitter*d
+func (policy Policy) jitter(d time.Duration) time.Duration {
+	if policy.MaxInterval > 0 && d > policy.MaxInterval {
+		d = policy.MaxInterval
+	}
+
+	if policy.Jitter <= 0 {
+		return d
+	}
+
+	// a jitter above 1 could make the delay negative
+	jitter := policy.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+
+	random := policy.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+
+	delta := jitter * float64(d)
+	return time.Duration(float64(d) - delta + 2*delta*random())
+}
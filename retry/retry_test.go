@@ -0,0 +1,209 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+// fakeClock never blocks: waiting on After advances the clock instantly
+type fakeClock struct {
+	now    time.Time
+	delays []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	c.now = c.now.Add(d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func failing(errs ...error) (func(ctx context.Context) error, *int) {
+	calls := 0
+	return func(ctx context.Context) error {
+		calls++
+		if calls > len(errs) {
+			return nil
+		}
+		return errs[calls-1]
+	}, &calls
+}
+
+func TestDoSucceeds(t *testing.T) {
+	clock := newFakeClock()
+	fn, calls := failing(errors.New("a"), errors.New("b"))
+
+	err := Do(context.Background(), fn, Policy{InitialInterval: time.Second, Clock: clock})
+	if err != nil {
+		t.Errorf("expected nil error, got=%v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("wrong number of calls: want=%v got=%v", 3, *calls)
+	}
+
+	want := []time.Duration{time.Second, 2 * time.Second}
+	if len(clock.delays) != len(want) {
+		t.Fatalf("wrong delays: want=%v got=%v", want, clock.delays)
+	}
+	for i := range want {
+		if clock.delays[i] != want[i] {
+			t.Errorf("wrong delay #%d: want=%v got=%v", i, want[i], clock.delays[i])
+		}
+	}
+}
+
+func TestDoMaxAttempts(t *testing.T) {
+	clock := newFakeClock()
+	last := errors.New("c")
+	fn, calls := failing(errors.New("a"), errors.New("b"), last, errors.New("d"))
+
+	err := Do(context.Background(), fn, Policy{MaxAttempts: 3, MaxInterval: 150 * time.Millisecond, Clock: clock})
+	if err == nil {
+		t.Fatal("expected non-nil error")
+	}
+	if *calls != 3 {
+		t.Errorf("wrong number of calls: want=%v got=%v", 3, *calls)
+	}
+	if !xerrs.IsEqual(err, last) {
+		t.Errorf("wrong cause: want=%v got=%v", last, xerrs.Cause(err))
+	}
+	if clock.delays[1] != 150*time.Millisecond {
+		t.Errorf("wrong capped delay: want=%v got=%v", 150*time.Millisecond, clock.delays[1])
+	}
+
+	v, ok := xerrs.GetData(err, AttemptsKey)
+	if !ok {
+		t.Fatal("expected attempts data")
+	}
+	attempts := v.(Attempts)
+	if len(attempts) != 3 {
+		t.Fatalf("wrong number of attempts: want=%v got=%v", 3, len(attempts))
+	}
+	if attempts[2].Number != 3 || attempts[2].Err != last || attempts[2].Start != 250*time.Millisecond {
+		t.Errorf("wrong last attempt: got=%v", attempts[2])
+	}
+
+	details := xerrs.Details(err, 0)
+	for _, want := range []string{"retry.attempts: #1 at +0s", "#3 at +250ms (took 0s): c"} {
+		if !strings.Contains(details, want) {
+			t.Errorf("expected details to contain %q, got=%v", want, details)
+		}
+	}
+}
+
+func TestDoPermanent(t *testing.T) {
+	clock := newFakeClock()
+	fn, calls := failing(errors.New("a"), Permanent(errors.New("b")), errors.New("c"))
+
+	err := Do(context.Background(), fn, Policy{Clock: clock})
+	if *calls != 2 {
+		t.Errorf("wrong number of calls: want=%v got=%v", 2, *calls)
+	}
+	if !IsPermanent(err) {
+		t.Errorf("expected permanent error, got=%v", err)
+	}
+	if Permanent(nil) != nil {
+		t.Error("expected nil error")
+	}
+
+	stack := xerrs.Stack(err)
+	if len(stack) == 0 || !strings.HasSuffix(stack[0].Function, ".TestDoPermanent") {
+		t.Errorf("wrong stack: want to start at TestDoPermanent got=%v", stack)
+	}
+
+	fn, calls = failing(fmt.Errorf("load: %w", Permanent(errors.New("a"))), errors.New("b"))
+	Do(context.Background(), fn, Policy{Clock: clock, MaxAttempts: 3})
+	if *calls != 1 {
+		t.Errorf("wrong number of calls for wrapped permanent error: want=%v got=%v", 1, *calls)
+	}
+}
+
+func TestAttemptJSON(t *testing.T) {
+	b, err := json.Marshal(Attempts{{Number: 1, Err: errors.New("a"), Duration: time.Second}, {Number: 2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `[{"number":1,"start":0,"duration":1000000000,"error":"a"},{"number":2,"start":0,"duration":0}]`
+	if string(b) != want {
+		t.Errorf("wrong JSON: want=%v got=%v", want, string(b))
+	}
+}
+
+func TestDoRetryAfter(t *testing.T) {
+	clock := newFakeClock()
+	fn, _ := failing(WithRetryAfter(errors.New("a"), 7*time.Second))
+
+	if err := Do(context.Background(), fn, Policy{Clock: clock}); err != nil {
+		t.Errorf("expected nil error, got=%v", err)
+	}
+	if len(clock.delays) != 1 || clock.delays[0] != 7*time.Second {
+		t.Errorf("wrong delays: want=%v got=%v", []time.Duration{7 * time.Second}, clock.delays)
+	}
+}
+
+func TestDoMaxElapsedTime(t *testing.T) {
+	clock := newFakeClock()
+	fn, calls := failing(errors.New("a"), errors.New("b"), errors.New("c"), errors.New("d"))
+
+	err := Do(context.Background(), fn, Policy{InitialInterval: time.Second, MaxElapsedTime: 4 * time.Second, Clock: clock})
+	if err == nil {
+		t.Fatal("expected non-nil error")
+	}
+	// delays are 1s and 2s, the next 4s delay would end after 7s
+	if *calls != 3 {
+		t.Errorf("wrong number of calls: want=%v got=%v", 3, *calls)
+	}
+}
+
+func TestDoContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fn, calls := failing(errors.New("a"), errors.New("b"))
+	err := Do(ctx, fn, Policy{InitialInterval: time.Hour})
+	if !xerrs.IsEqual(err, context.Canceled) {
+		t.Errorf("wrong cause: want=%v got=%v", context.Canceled, xerrs.Cause(err))
+	}
+	if *calls != 1 {
+		t.Errorf("wrong number of calls: want=%v got=%v", 1, *calls)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for _, test := range []struct {
+		random float64
+		want   time.Duration
+	}{
+		{random: 0, want: 500 * time.Millisecond},
+		{random: 0.5, want: time.Second},
+		{random: 0.999, want: 1499 * time.Millisecond},
+	} {
+		policy := Policy{Jitter: 0.5, Rand: func() float64 { return test.random }}
+		if got := policy.jitter(time.Second).Round(time.Millisecond); got != test.want {
+			t.Errorf("wrong jitter for %v: want=%v got=%v", test.random, test.want, got)
+		}
+	}
+}
+
+func TestJitterAboveOne(t *testing.T) {
+	policy := Policy{Jitter: 3, Rand: func() float64 { return 0 }}
+	if got := policy.jitter(time.Second); got != 0 {
+		t.Errorf("wrong jitter: want=%v got=%v", time.Duration(0), got)
+	}
+}
@@ -0,0 +1,85 @@
+package xerrs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Severity - how serious an error is
+type Severity int
+
+// Severity levels in increasing order. SeverityUnset is lower than every level.
+const (
+	SeverityUnset Severity = iota
+	SeverityDebug
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = []string{"", "debug", "info", "warning", "error", "critical"}
+
+// Returns the lower case name of the severity
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+
+	return severityNames[s]
+}
+
+// ParseSeverity - returns the Severity with the given name
+func ParseSeverity(name string) (Severity, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "warn" {
+		return SeverityWarning, nil
+	}
+
+	for i, severityName := range severityNames {
+		if severityName == name {
+			return Severity(i), nil
+		}
+	}
+
+	return SeverityUnset, Errorf("unknown severity %q", name)
+}
+
+// MarshalText - encodes the severity name
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText - decodes a severity name
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = severity
+	return nil
+}
+
+// SetSeverity - sets the severity of xerr in place. To set it when an error is
+// created or wrapped, pass WithSeverity to New, Errorf, Extend, Wrap or Wrapf.
+// If err is not xerr then nothing happens
+func SetSeverity(err error, severity Severity) {
+	if x, ok := err.(*xerr); ok {
+		x.severity = severity
+	}
+}
+
+// SeverityOf - returns the highest severity set on any error in the chain
+// If no severity is set then SeverityUnset is returned
+func SeverityOf(err error) Severity {
+	severity := SeverityUnset
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(*xerr); ok && x.severity > severity {
+			severity = x.severity
+		}
+	}
+
+	return severity
+}
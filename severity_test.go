@@ -0,0 +1,108 @@
+package xerrs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSeverity(t *testing.T) {
+	for _, severity := range []Severity{SeverityDebug, SeverityInfo, SeverityWarning, SeverityError, SeverityCritical} {
+		text, _ := severity.MarshalText()
+
+		var got Severity
+		if err := got.UnmarshalText(text); err != nil || got != severity {
+			t.Errorf("wrong severity for %q: want=%v got=%v err=%v", text, severity, got, err)
+		}
+	}
+
+	if got, _ := ParseSeverity("WARN"); got != SeverityWarning {
+		t.Errorf("wrong severity: want=%v got=%v", SeverityWarning, got)
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+	if !(SeverityUnset < SeverityDebug && SeverityError < SeverityCritical) {
+		t.Error("expected severities to be ordered")
+	}
+}
+
+func TestSeverityOf(t *testing.T) {
+	inner := New("ABC")
+	SetSeverity(inner, SeverityWarning)
+
+	outer := Wrap(inner, "wrap")
+	if got := SeverityOf(outer); got != SeverityWarning {
+		t.Errorf("wrong inherited severity: want=%v got=%v", SeverityWarning, got)
+	}
+
+	SetSeverity(outer, SeverityInfo)
+	if got := SeverityOf(outer); got != SeverityWarning {
+		t.Errorf("expected max severity to win: want=%v got=%v", SeverityWarning, got)
+	}
+
+	SetSeverity(outer, SeverityCritical)
+	if got := SeverityOf(outer); got != SeverityCritical {
+		t.Errorf("expected max severity to win: want=%v got=%v", SeverityCritical, got)
+	}
+
+	if got := SeverityOf(Wrap(inner, "wrap", WithSeverity(SeverityCritical))); got != SeverityCritical {
+		t.Errorf("wrong wrap severity: want=%v got=%v", SeverityCritical, got)
+	}
+
+	if got := SeverityOf(New("ABC", WithSeverity(SeverityDebug))); got != SeverityDebug {
+		t.Errorf("wrong creation severity: want=%v got=%v", SeverityDebug, got)
+	}
+
+	if got := SeverityOf(errTestCarrierTimeout.New()); got != SeverityError {
+		t.Errorf("wrong definition severity: want=%v got=%v", SeverityError, got)
+	}
+
+	if got := SeverityOf(errors.New("ABC")); got != SeverityUnset {
+		t.Errorf("wrong severity: want=%v got=%v", SeverityUnset, got)
+	}
+
+	if got := Details(outer, 0); !strings.Contains(got, "\n[SEVERITY] critical\n") {
+		t.Errorf("expected severity in details, got=%v", got)
+	}
+
+	b, _ := json.Marshal(outer)
+	if !strings.Contains(string(b), `"severity":"critical"`) {
+		t.Errorf("expected severity in JSON, got=%v", string(b))
+	}
+}
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	for _, test := range []struct {
+		severity Severity
+		want     string
+	}{
+		{SeverityUnset, `"level":"ERROR"`},
+		{SeverityDebug, `"level":"DEBUG"`},
+		{SeverityWarning, `"level":"WARN"`},
+		{SeverityCritical, `"level":"ERROR+4"`},
+	} {
+		buf.Reset()
+
+		err := New("ABC")
+		SetSeverity(err, test.severity)
+		Log(context.Background(), logger, "failed", err)
+
+		if !strings.Contains(buf.String(), test.want) {
+			t.Errorf("wrong level for %v: want=%v got=%v", test.severity, test.want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	Log(context.Background(), logger, "failed", nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing logged for nil error, got=%v", buf.String())
+	}
+}
@@ -0,0 +1,146 @@
+package xerrs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// Sink - receives the errors delivered by a Reporter, one at a time
+type Sink interface {
+	Send(ctx context.Context, err error) error
+}
+
+// SinkFunc - adapts a function to Sink
+type SinkFunc func(ctx context.Context, err error) error
+
+// Send - calls f
+func (f SinkFunc) Send(ctx context.Context, err error) error {
+	return f(ctx, err)
+}
+
+// WriterSink - writes the Details of every error to a writer
+type WriterSink struct {
+	mu       sync.Mutex
+	w        io.Writer
+	maxStack int
+}
+
+// NewWriterSink - returns a sink which writes Details(err, maxStack) to w
+func NewWriterSink(w io.Writer, maxStack int) *WriterSink {
+	return &WriterSink{w: w, maxStack: maxStack}
+}
+
+// Send - writes the Details of err followed by an empty line
+func (s *WriterSink) Send(ctx context.Context, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, e := fmt.Fprintf(s.w, "%s\n\n", Details(err, s.maxStack))
+	return Extend(e)
+}
+
+// SlogSink - logs every error with Log
+type SlogSink struct {
+	Logger *slog.Logger
+	// Message is the log message. Defaults to "error reported".
+	Message string
+}
+
+// Send - logs err at the level of its severity
+func (s SlogSink) Send(ctx context.Context, err error) error {
+	message := s.Message
+	if message == "" {
+		message = "error reported"
+	}
+
+	Log(ctx, s.Logger, message, err)
+	return nil
+}
+
+// WebhookSink - posts every error as JSON to a URL
+type WebhookSink struct {
+	URL string
+	// Client is used to send the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+	// Header is added to every request, e.g. for authentication.
+	Header http.Header
+}
+
+// Send - posts the JSON encoding of err. Errors which are not xerr are posted
+// with their message only.
+func (s WebhookSink) Send(ctx context.Context, err error) error {
+	var body []byte
+	var e error
+	if _, ok := err.(*xerr); ok {
+		body, e = json.Marshal(err)
+	} else {
+		body, e = json.Marshal(Record{Error: Scrub(err.Error()), Cause: Scrub(err.Error())})
+	}
+	if e != nil {
+		return Extend(e)
+	}
+
+	req, e := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if e != nil {
+		return Extend(e)
+	}
+	for name, values := range s.Header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, e := client.Do(req)
+	if e != nil {
+		return Extend(e)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return Errorf("webhook: unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// MemorySink - keeps every error in memory, for tests
+type MemorySink struct {
+	mu     sync.Mutex
+	errors []error
+}
+
+// Send - appends err to the errors of the sink
+func (s *MemorySink) Send(ctx context.Context, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = append(s.errors, err)
+	return nil
+}
+
+// Errors - returns the errors received so far
+func (s *MemorySink) Errors() []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]error(nil), s.errors...)
+}
+
+// Reset - forgets the errors received so far
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = nil
+}
@@ -0,0 +1,95 @@
+package xerrs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	err := New("ABC")
+
+	if e := NewWriterSink(&buf, 1).Send(context.Background(), err); e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	if want := Details(err, 1) + "\n\n"; buf.String() != want {
+		t.Errorf("wrong output: want=%q got=%q", want, buf.String())
+	}
+}
+
+func TestSlogSink(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	err := New("ABC")
+	SetSeverity(err, SeverityWarning)
+	SlogSink{Logger: logger}.Send(context.Background(), err)
+
+	for _, want := range []string{"level=WARN", `msg="error reported"`, "err.error=ABC"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected log to contain %q, got=%v", want, buf.String())
+		}
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var bodies []map[string]interface{}
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		tokens = append(tokens, r.Header.Get("Authorization"))
+
+		if body["cause"] == "reject" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	sink := WebhookSink{URL: server.URL, Header: http.Header{"Authorization": {"Bearer token"}}}
+	ctx := context.Background()
+
+	if e := sink.Send(ctx, New("ABC")); e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+	if e := sink.Send(ctx, errors.New("plain")); e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+	if e := sink.Send(ctx, errors.New("reject")); e == nil {
+		t.Errorf("expected an error for status 400")
+	}
+
+	if bodies[0]["cause"] != "ABC" || bodies[0]["stack"] == nil {
+		t.Errorf("wrong body for xerr: %v", bodies[0])
+	}
+	if bodies[1]["error"] != "plain" {
+		t.Errorf("wrong body for plain error: %v", bodies[1])
+	}
+	if tokens[0] != "Bearer token" {
+		t.Errorf("wrong authorization header: want=%v got=%v", "Bearer token", tokens[0])
+	}
+}
+
+func TestMemorySink(t *testing.T) {
+	sink := &MemorySink{}
+	err := New("ABC")
+	sink.Send(context.Background(), err)
+
+	if got := sink.Errors(); len(got) != 1 || got[0] != err {
+		t.Errorf("wrong errors: want=%v got=%v", []error{err}, got)
+	}
+
+	sink.Reset()
+	if got := sink.Errors(); len(got) != 0 {
+		t.Errorf("wrong errors after reset: want=%v got=%v", nil, got)
+	}
+}
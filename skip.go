@@ -0,0 +1,64 @@
+package xerrs
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var helpers struct {
+	functions sync.Map
+	count     atomic.Int32
+}
+
+// NewSkip - creates a new xerr like New, leaving the skip innermost stack
+// locations out of its stack
+// skip 0 is the caller of NewSkip, 1 is its caller, and so forth
+func NewSkip(skip int, message string, opts ...Option) error {
+	return New(message, append([]Option{WithSkip(skip + 1)}, opts...)...)
+}
+
+// ExtendSkip - creates a new xerr like Extend, leaving the skip innermost
+// stack locations out of its stack
+// If err is nil then nil is returned
+func ExtendSkip(skip int, err error, opts ...Option) error {
+	return Extend(err, append([]Option{WithSkip(skip + 1)}, opts...)...)
+}
+
+// WrapSkip - creates a new xerr like Wrap, leaving the skip innermost stack
+// locations out of its stack
+// If err is nil then nil is returned
+func WrapSkip(skip int, err error, message string, opts ...Option) error {
+	return Wrap(err, message, append([]Option{WithSkip(skip + 1)}, opts...)...)
+}
+
+// Helper - marks the calling function as a helper which creates errors, like
+// testing.T.Helper. Helpers are left out of the beginning of every stack, so
+// the stack of an error starts at the code which called the helper.
+func Helper() {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return
+	}
+
+	name := runtime.FuncForPC(pc).Name()
+	if _, loaded := helpers.functions.LoadOrStore(name, struct{}{}); !loaded {
+		helpers.count.Add(1)
+	}
+}
+
+// trimHelpers removes the leading stack locations of registered helpers
+func trimHelpers(stack []StackLocation) []StackLocation {
+	if helpers.count.Load() == 0 {
+		return stack
+	}
+
+	for len(stack) > 1 {
+		if _, ok := helpers.functions.Load(stack[0].Function); !ok {
+			break
+		}
+		stack = stack[1:]
+	}
+
+	return stack
+}
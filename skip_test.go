@@ -0,0 +1,50 @@
+package xerrs
+
+import (
+	"errors"
+	"testing"
+)
+
+func skipHelper(err error) error {
+	return ExtendSkip(1, err)
+}
+
+func markedHelper(err error) error {
+	Helper()
+	return Wrap(err, "helper")
+}
+
+func nestedMarkedHelper(err error) error {
+	Helper()
+	return markedHelper(err)
+}
+
+func TestSkip(t *testing.T) {
+	const origin = "github.com/RoseRocket/xerrs.TestSkip"
+
+	cases := []struct {
+		name string
+		err  error
+	}{
+		{"NewSkip", NewSkip(0, "ABC")},
+		{"ExtendSkip", ExtendSkip(0, errors.New("ABC"))},
+		{"WrapSkip", WrapSkip(0, errors.New("ABC"), "wrapped")},
+		{"skipping helper", skipHelper(errors.New("ABC"))},
+		{"marked helper", markedHelper(errors.New("ABC"))},
+		{"nested marked helpers", nestedMarkedHelper(errors.New("ABC"))},
+	}
+
+	for _, c := range cases {
+		if got := Stack(c.err)[0].Function; got != origin {
+			t.Errorf("wrong origin for %s: want=%v got=%v", c.name, origin, got)
+		}
+	}
+
+	if err := WrapSkip(0, nil, "wrapped"); err != nil {
+		t.Errorf("expected nil, got=%v", err)
+	}
+
+	if got := Internal(WrapSkip(0, errors.New("ABC"), "wrapped", WithCode("db"))); got != "wrapped: ABC" {
+		t.Errorf("wrong message: want=%v got=%v", "wrapped: ABC", got)
+	}
+}
@@ -0,0 +1,93 @@
+package xerrs
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// LogValue - implements slog.LogValuer so that xerr is logged as a group with
+// its cause, mask, severity, creation time and goroutine when tracked,
+// template, args, data merged from its xerr causes and stack
+// The error attribute is the internal message, see Internal
+// Sensitive data and registered scrubbers are applied to the output
+func (x *xerr) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("error", Scrub(Internal(x))),
+		slog.String("cause", Scrub(Internal(x.cause))),
+	}
+
+	if x.mask != nil {
+		attrs = append(attrs, slog.String("mask", Scrub(x.mask.Error())))
+	}
+
+	if severity := SeverityOf(x); severity != SeverityUnset {
+		attrs = append(attrs, slog.String("severity", severity.String()))
+	}
+
+	if created := Time(x); !created.IsZero() {
+		attrs = append(attrs, slog.Time("time", created), slog.Uint64("goroutine", Goroutine(x)))
+	}
+
+	attrs = append(attrs, slog.String("template", Scrub(Template(x))))
+
+	if args := Args(x); len(args) > 0 {
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = jsonValue(RedactValue("", arg))
+		}
+		attrs = append(attrs, slog.Any("args", values))
+	}
+
+	if data := Data(x); len(data) > 0 {
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		group := make([]slog.Attr, len(keys))
+		for i, key := range keys {
+			group[i] = slog.Any(key, RedactValue(key, data[key]))
+		}
+		attrs = append(attrs, slog.Attr{Key: "data", Value: slog.GroupValue(group...)})
+	}
+
+	if len(x.stack) > 0 {
+		stack := make([]string, len(x.stack))
+		for i, location := range x.stack {
+			stack[i] = location.String()
+		}
+		attrs = append(attrs, slog.Any("stack", stack))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// Level - returns the slog level matching the severity
+// SeverityUnset is logged at slog.LevelError and SeverityCritical above it
+func (s Severity) Level() slog.Level {
+	switch s {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarning:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return slog.LevelError + 4
+	}
+
+	return slog.LevelError
+}
+
+// Log - logs err with logger at the level matching SeverityOf(err)
+// err is added to args under the "err" key
+// If err is nil then nothing is logged
+func Log(ctx context.Context, logger *slog.Logger, msg string, err error, args ...interface{}) {
+	if err == nil {
+		return
+	}
+
+	logger.Log(ctx, SeverityOf(err).Level(), msg, append(args, slog.Any("err", err))...)
+}
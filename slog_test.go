@@ -0,0 +1,41 @@
+package xerrs
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	err := Wrap(Errorf("shipment %d not found", 123, WithData("host", "db1")), "load")
+	SetData(err, "carrier", "ups")
+	logger.Error("failed", "err", err)
+
+	var got struct {
+		Err map[string]interface{} `json:"err"`
+	}
+	if e := json.Unmarshal(buf.Bytes(), &got); e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	for key, want := range map[string]interface{}{
+		"error":    "load: shipment 123 not found",
+		"cause":    "shipment 123 not found",
+		"template": "load: shipment %d not found",
+		"args":     []interface{}{float64(123)},
+		"data":     map[string]interface{}{"carrier": "ups", "host": "db1"},
+	} {
+		if !reflect.DeepEqual(got.Err[key], want) {
+			t.Errorf("wrong %s: want=%v got=%v", key, want, got.Err[key])
+		}
+	}
+
+	if _, ok := got.Err["mask"]; ok {
+		t.Errorf("expected no mask, got=%v", got.Err["mask"])
+	}
+}
@@ -0,0 +1,133 @@
+package xerrs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SourceRenderer - a development renderer which prints Details followed by a
+// few lines of source code around every stack location.
+// Frames whose files can not be read on this machine are printed without source.
+type SourceRenderer struct {
+	// Context is the number of lines printed before and after the failing line.
+	// Defaults to 2.
+	Context int
+	// MaxStack is the number of stack locations printed. Zero means all of them.
+	MaxStack int
+	// Color controls ANSI colors. ColorAuto colors output written to a terminal.
+	Color ColorMode
+}
+
+// SourceDetails - returns Details with source code around the first maxStack
+// stack locations, without colors
+// If err is not xerr then err.Error() with registered scrubbers applied is returned
+func SourceDetails(err error, maxStack int) string {
+	var b strings.Builder
+	SourceRenderer{MaxStack: maxStack, Color: ColorNever}.Render(&b, err)
+
+	return b.String()
+}
+
+// Render - writes err with source code around its stack locations to w
+func (r SourceRenderer) Render(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	x, ok := err.(*xerr)
+	if !ok {
+		_, e := fmt.Fprintln(w, Scrub(err.Error()))
+		return e
+	}
+
+	color := colorizer(r.Color.enabled(w))
+	context := r.Context
+	if context <= 0 {
+		context = 2
+	}
+
+	var b strings.Builder
+	for _, line := range detailsHeader(x) {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	stack := x.stack
+	if r.MaxStack > 0 && r.MaxStack < len(stack) {
+		stack = stack[:r.MaxStack]
+	}
+
+	if len(stack) > 0 {
+		b.WriteString("[STACK]:\n")
+	}
+
+	for _, location := range stack {
+		b.WriteString(color.paint(location.String(), ansiBold))
+		b.WriteByte('\n')
+
+		lines := sourceLines(location.File)
+		if lines == nil || location.Line < 1 || location.Line > len(lines) {
+			continue
+		}
+
+		first := location.Line - context
+		if first < 1 {
+			first = 1
+		}
+		last := location.Line + context
+		if last > len(lines) {
+			last = len(lines)
+		}
+
+		width := len(fmt.Sprint(last))
+		for n := first; n <= last; n++ {
+			text := strings.ReplaceAll(lines[n-1], "\t", "    ")
+			if n == location.Line {
+				b.WriteString(color.paint(fmt.Sprintf("> %*d | %s", width, n, text), ansiBold, ansiRed))
+			} else {
+				b.WriteString(color.paint(fmt.Sprintf("  %*d | %s", width, n, text), ansiDim))
+			}
+			b.WriteByte('\n')
+		}
+	}
+
+	_, e := io.WriteString(w, b.String())
+	return e
+}
+
+var sourceCache = struct {
+	sync.Mutex
+	files map[string][]string
+}{
+	files: make(map[string][]string),
+}
+
+// sourceLines returns the lines of file, caching every read
+// If the file can not be read then nil is returned
+func sourceLines(file string) []string {
+	sourceCache.Lock()
+	defer sourceCache.Unlock()
+
+	if lines, ok := sourceCache.files[file]; ok {
+		return lines
+	}
+
+	var lines []string
+	if f, err := os.Open(file); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if scanner.Err() != nil {
+			lines = nil
+		}
+		f.Close()
+	}
+
+	sourceCache.files[file] = lines
+	return lines
+}
@@ -0,0 +1,73 @@
+package xerrs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSourceDetails(t *testing.T) {
+	err := New("ABC") // source line under test
+	line := Stack(err)[0].Line
+
+	got := SourceDetails(err, 1)
+	for _, want := range []string{
+		"[ERROR] ABC\n[STACK]:\n",
+		fmt.Sprintf("  %d | ", line-1),
+		fmt.Sprintf("> %d |     err := New(\"ABC\") // source line under test\n", line),
+		fmt.Sprintf("  %d |     line := Stack(err)[0].Line\n", line+1),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got=%v", want, got)
+		}
+	}
+
+	if strings.Contains(got, "testing.tRunner") {
+		t.Errorf("expected only one stack location, got=%v", got)
+	}
+
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("expected no colors, got=%q", got)
+	}
+
+	defer ResetRedaction()
+	RegisterScrubber(EmailScrubber)
+	if got := SourceDetails(errors.New("user jane@example.com not found"), 1); got != "user [REDACTED] not found\n" {
+		t.Errorf("wrong output for basic error: want=%q got=%q", "user [REDACTED] not found\n", got)
+	}
+}
+
+func TestSourceRenderer(t *testing.T) {
+	err := &xerr{
+		cause: fmt.Errorf("ABC"),
+		stack: []StackLocation{
+			{Function: "main.missing", File: "/does/not/exist.go", Line: 10},
+			getStack(1)[0],
+		},
+	}
+
+	var buf bytes.Buffer
+	if e := (SourceRenderer{Context: 1, Color: ColorAlways}).Render(&buf, err); e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "main.missing [/does/not/exist.go:10]"+ansiReset+"\n"+ansiBold) {
+		t.Errorf("expected missing file to be skipped, got=%q", got)
+	}
+	if !strings.Contains(got, ansiBold+ansiRed+"> ") {
+		t.Errorf("expected highlighted line, got=%q", got)
+	}
+	if n := strings.Count(got, " | "); n != 3 {
+		t.Errorf("wrong number of source lines: want=%v got=%v", 3, n)
+	}
+
+	buf.Reset()
+	t.Setenv("NO_COLOR", "1")
+	(SourceRenderer{}).Render(&buf, err)
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("expected no colors, got=%q", buf.String())
+	}
+}
@@ -0,0 +1,58 @@
+package xerrs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StackOptions - controls how stacks are compared by SameStack and StackKey
+type StackOptions struct {
+	// IgnoreLines compares functions and files only, so stacks match across
+	// unrelated code changes.
+	IgnoreLines bool
+	// IgnoreVendor leaves out frames of vendored and module cache dependencies.
+	IgnoreVendor bool
+}
+
+// SameStack - reports whether a and b were created at the same stack
+// If a or b is not xerr then false is returned
+func SameStack(a, b error, opts StackOptions) bool {
+	x, ok := a.(*xerr)
+	if !ok {
+		return false
+	}
+
+	y, ok := b.(*xerr)
+	if !ok {
+		return false
+	}
+
+	return StackKey(x.stack, opts) == StackKey(y.stack, opts)
+}
+
+// StackKey - returns a normalized representation of stack which is equal for
+// stacks considered the same under opts
+func StackKey(stack []StackLocation, opts StackOptions) string {
+	frames := make([]string, 0, len(stack))
+	for _, location := range stack {
+		if opts.IgnoreVendor && isVendored(location.File) {
+			continue
+		}
+
+		frame := location.Function + " " + location.File
+		if !opts.IgnoreLines {
+			frame = fmt.Sprintf("%s:%d", frame, location.Line)
+		}
+		frames = append(frames, frame)
+	}
+
+	return strings.Join(frames, "\n")
+}
+
+// isVendored reports whether file belongs to a dependency in a vendor
+// directory or in the module cache
+func isVendored(file string) bool {
+	file = strings.ReplaceAll(file, "\\", "/")
+
+	return strings.Contains(file, "/vendor/") || strings.Contains(file, "/pkg/mod/")
+}
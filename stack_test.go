@@ -0,0 +1,43 @@
+package xerrs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSameStack(t *testing.T) {
+	newErr := func() error { return New("ABC") }
+
+	a, b := newErr(), newErr()
+	c := New("ABC")
+
+	vendored := func(line int) error {
+		return &xerr{
+			cause: fmt.Errorf("ABC"),
+			stack: []StackLocation{
+				{Function: "example.com/lib.Do", File: "/app/vendor/example.com/lib/do.go", Line: line},
+				{Function: "main.main", File: "/app/main.go", Line: 10},
+			},
+		}
+	}
+
+	cases := []struct {
+		name string
+		a, b error
+		opts StackOptions
+		want bool
+	}{
+		{"same origin", a, b, StackOptions{}, true},
+		{"different origin", a, c, StackOptions{}, false},
+		{"vendored lines differ", vendored(1), vendored(2), StackOptions{}, false},
+		{"ignore lines", vendored(1), vendored(2), StackOptions{IgnoreLines: true}, true},
+		{"ignore vendor", vendored(1), vendored(2), StackOptions{IgnoreVendor: true}, true},
+		{"not xerr", fmt.Errorf("ABC"), fmt.Errorf("ABC"), StackOptions{}, false},
+	}
+
+	for _, c := range cases {
+		if got := SameStack(c.a, c.b, c.opts); got != c.want {
+			t.Errorf("wrong result for %s: want=%v got=%v", c.name, c.want, got)
+		}
+	}
+}
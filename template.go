@@ -0,0 +1,54 @@
+package xerrs
+
+// Template - returns the message of err with the Errorf and Wrapf format strings
+// kept in place of the formatted values, e.g. "load: shipment %d not found"
+// Mask is ignored, the template always describes the underlying error
+// If err is not xerr then err.Error() is returned
+// If err is nil then "" is returned
+func Template(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	x, ok := err.(*xerr)
+	if !ok {
+		return err.Error()
+	}
+
+	// Errorf keeps its format in place of the cause message
+	if x.msg == "" && x.format != "" {
+		return x.format
+	}
+
+	head := x.msg
+	if x.format != "" {
+		head = x.format
+	}
+
+	if head == "" {
+		return Template(x.cause)
+	}
+
+	return head + ": " + Template(x.cause)
+}
+
+// Args - returns the Errorf and Wrapf arguments of err in the order in which
+// they appear in Template(err)
+// If err is not xerr then nil is returned
+func Args(err error) []interface{} {
+	x, ok := err.(*xerr)
+	if !ok {
+		return nil
+	}
+
+	if x.msg == "" && x.format != "" {
+		return x.args
+	}
+
+	args := Args(x.cause)
+	if len(x.args) == 0 {
+		return args
+	}
+
+	return append(append([]interface{}{}, x.args...), args...)
+}
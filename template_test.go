@@ -0,0 +1,68 @@
+package xerrs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	for _, test := range []struct {
+		description  string
+		in           error
+		wantTemplate string
+		wantArgs     []interface{}
+	}{
+		{
+			description:  "nil error",
+			in:           nil,
+			wantTemplate: "",
+			wantArgs:     nil,
+		},
+		{
+			description:  "basic error",
+			in:           errors.New("ABC"),
+			wantTemplate: "ABC",
+			wantArgs:     nil,
+		},
+		{
+			description:  "new",
+			in:           New("ABC"),
+			wantTemplate: "ABC",
+			wantArgs:     nil,
+		},
+		{
+			description:  "errorf",
+			in:           Errorf("shipment %d not found", 123),
+			wantTemplate: "shipment %d not found",
+			wantArgs:     []interface{}{123},
+		},
+		{
+			description:  "wrap errorf",
+			in:           Wrap(Errorf("shipment %d not found", 123), "load"),
+			wantTemplate: "load: shipment %d not found",
+			wantArgs:     []interface{}{123},
+		},
+		{
+			description:  "wrapf errorf",
+			in:           Wrapf(Errorf("shipment %d not found", 123), "carrier %q", "ups"),
+			wantTemplate: "carrier %q: shipment %d not found",
+			wantArgs:     []interface{}{"ups", 123},
+		},
+		{
+			description:  "masked errorf",
+			in:           Mask(Errorf("shipment %d not found", 123), errors.New("not found")),
+			wantTemplate: "shipment %d not found",
+			wantArgs:     []interface{}{123},
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			if got := Template(test.in); got != test.wantTemplate {
+				t.Errorf("wrong template: want=%v got=%v", test.wantTemplate, got)
+			}
+			if got := Args(test.in); !reflect.DeepEqual(got, test.wantArgs) {
+				t.Errorf("wrong args: want=%v got=%v", test.wantArgs, got)
+			}
+		})
+	}
+}
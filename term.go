@@ -0,0 +1,74 @@
+package xerrs
+
+import (
+	"io"
+	"os"
+)
+
+// ColorMode - controls whether renderers write ANSI colors
+type ColorMode int
+
+// Color modes. ColorAuto writes colors only to terminals and respects NO_COLOR.
+const (
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+// ANSI escape sequences used by the renderers
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// enabled reports whether colors should be written to w
+func (mode ColorMode) enabled(w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a character device such as a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorizer wraps text in ANSI escape sequences when enabled
+type colorizer bool
+
+func (c colorizer) paint(text string, codes ...string) string {
+	if !c || text == "" {
+		return text
+	}
+
+	prefix := ""
+	for _, code := range codes {
+		prefix += code
+	}
+
+	return prefix + text + ansiReset
+}
@@ -0,0 +1,100 @@
+package xerrs
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var tracking struct {
+	enabled atomic.Bool
+
+	sync.RWMutex
+	now func() time.Time
+}
+
+// EnableTracking - turns recording of the creation time and goroutine of every
+// new error on or off. It is off by default because reading the goroutine ID
+// costs a call to runtime.Stack.
+func EnableTracking(enabled bool) {
+	tracking.enabled.Store(enabled)
+}
+
+// SetClock - sets the function which returns the creation time of errors
+// If now is nil then time.Now is used
+func SetClock(now func() time.Time) {
+	tracking.Lock()
+	defer tracking.Unlock()
+
+	tracking.now = now
+}
+
+// Time - returns when the original error in the chain was created
+// If tracking was disabled at that time then the zero time is returned
+func Time(err error) time.Time {
+	if x := trackedOrigin(err); x != nil {
+		return x.created
+	}
+
+	return time.Time{}
+}
+
+// Goroutine - returns the ID of the goroutine which created the original error in the chain
+// If tracking was disabled at that time then 0 is returned
+func Goroutine(err error) uint64 {
+	if x := trackedOrigin(err); x != nil {
+		return x.goroutine
+	}
+
+	return 0
+}
+
+// trackedOrigin returns the innermost xerr which recorded its creation
+func trackedOrigin(err error) *xerr {
+	var origin *xerr
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(*xerr); ok && !x.created.IsZero() {
+			origin = x
+		}
+	}
+
+	return origin
+}
+
+// track records the creation time and goroutine of x if tracking is enabled
+func track(x *xerr) *xerr {
+	if !tracking.enabled.Load() {
+		return x
+	}
+
+	tracking.RLock()
+	now := tracking.now
+	tracking.RUnlock()
+
+	if now == nil {
+		now = time.Now
+	}
+
+	x.created = now()
+	x.goroutine = goroutineID()
+	return x
+}
+
+// goroutineID parses the current goroutine ID from the "goroutine 123 [running]:"
+// header of runtime.Stack
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i >= 0 {
+		header = header[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
@@ -0,0 +1,55 @@
+package xerrs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTracking(t *testing.T) {
+	if got := Time(New("ABC")); !got.IsZero() {
+		t.Errorf("expected no time when tracking is disabled, got=%v", got)
+	}
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	EnableTracking(true)
+	SetClock(func() time.Time { return now })
+	defer func() {
+		EnableTracking(false)
+		SetClock(nil)
+	}()
+
+	inner := New("ABC")
+	now = now.Add(time.Minute)
+
+	done := make(chan error)
+	go func() {
+		done <- Wrap(inner, "wrap")
+	}()
+	outer := <-done
+
+	if got := Time(outer); !got.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("wrong time: want=%v got=%v", "2020-01-02T03:04:05Z", got)
+	}
+
+	if Goroutine(inner) == 0 || Goroutine(inner) != goroutineID() {
+		t.Errorf("wrong goroutine: want=%v got=%v", goroutineID(), Goroutine(inner))
+	}
+	if Goroutine(outer) != Goroutine(inner) {
+		t.Errorf("expected goroutine of the original error: want=%v got=%v", Goroutine(inner), Goroutine(outer))
+	}
+	if x := outer.(*xerr); x.goroutine == Goroutine(inner) {
+		t.Errorf("expected wrap to record its own goroutine, got=%v", x.goroutine)
+	}
+
+	details := Details(outer, 0)
+	if !strings.Contains(details, "\n[TIME] 2020-01-02T03:04:05Z\n[GOROUTINE] ") {
+		t.Errorf("expected time and goroutine in details, got=%v", details)
+	}
+
+	b, _ := json.Marshal(outer)
+	if !strings.Contains(string(b), `"time":"2020-01-02T03:04:05Z","goroutine":`) {
+		t.Errorf("expected time and goroutine in JSON, got=%v", string(b))
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"sort"
 	"strings"
+	"time"
 )
 
 // This value represents the offset in the stack array. We want to keep this
@@ -12,11 +14,20 @@ import (
 const stackFunctionOffset = 2
 
 type xerr struct {
-	data  map[string]interface{}
-	cause error
-	mask  error
-	stack []StackLocation
-	msg   string
+	data        map[string]interface{}
+	cause       error
+	mask        error
+	stack       []StackLocation
+	msg         string
+	format      string
+	args        []interface{}
+	fingerprint string
+	public      bool
+	def         *Definition
+	code        string
+	severity    Severity
+	created     time.Time
+	goroutine   uint64
 }
 
 func (x *xerr) Error() string {
@@ -31,6 +42,16 @@ func (x *xerr) Error() string {
 	return x.cause.Error()
 }
 
+// Unwrap - returns the cause so that xerr works with errors.Is and errors.As
+func (x *xerr) Unwrap() error {
+	return x.cause
+}
+
+// Is - reports whether xerr was created from the target Definition
+func (x *xerr) Is(target error) bool {
+	return x.def != nil && x.def == target
+}
+
 // StackLocation - A helper struct function which represents one step in the execution stack
 type StackLocation struct {
 	Function string `json:"function"`
@@ -44,41 +65,43 @@ func (location StackLocation) String() string {
 }
 
 // New - creates a new xerr with a supplied message.
-// It will also set the stack.
-func New(message string) error {
-	return &xerr{
+// It will also set the stack, unless the NoStack option is given.
+func New(message string, opts ...Option) error {
+	return build(&xerr{
 		data:  nil,
 		cause: errors.New(message),
 		mask:  nil,
-		stack: getStack(stackFunctionOffset),
-	}
+	}, opts)
 }
 
 // Errorf - creates a new xerr based on a formatted message.
-// It will also set the stack.
+// Option values in args are applied to the error instead of being formatted.
+// It will also set the stack, unless the NoStack option is given.
 func Errorf(format string, args ...interface{}) error {
-	return &xerr{
-		data:  nil,
-		cause: fmt.Errorf(format, args...),
-		mask:  nil,
-		stack: getStack(stackFunctionOffset),
-	}
+	args, opts := splitOptions(args)
+
+	return build(&xerr{
+		data:   nil,
+		cause:  fmt.Errorf(format, args...),
+		mask:   nil,
+		format: format,
+		args:   args,
+	}, opts)
 }
 
 // Extend - creates a new xerr based on a supplied error.
 // If err is nil then nil is returned
-// It will also set the stack.
-func Extend(err error) error {
+// It will also set the stack, unless the NoStack option is given.
+func Extend(err error, opts ...Option) error {
 	if err == nil {
 		return nil
 	}
 
-	return &xerr{
+	return build(&xerr{
 		data:  nil,
 		cause: err,
 		mask:  nil,
-		stack: getStack(stackFunctionOffset),
-	}
+	}, opts)
 }
 
 // Mask - creates a new xerr based on a supplied error but also sets the mask error as well
@@ -96,12 +119,11 @@ func Mask(err, mask error) error {
 		return x
 	}
 
-	return &xerr{
+	return build(&xerr{
 		data:  nil,
 		cause: err,
 		mask:  mask,
-		stack: getStack(stackFunctionOffset),
-	}
+	}, nil)
 }
 
 // IsEqual - helper function to compare if two erros are equal
@@ -142,19 +164,36 @@ func GetData(err error, name string) (value interface{}, ok bool) {
 		return nil, false
 	}
 
-	if x.data == nil && x.cause != nil {
-		// Check to see if x.cause is an *xerr as well, and see if it
-		// has the data at key name.
-		if cause, ok := x.cause.(*xerr); ok {
-			return GetData(cause, name)
+	if v, ok := x.data[name]; ok {
+		return v, true
+	}
+
+	// Check to see if x.cause is an *xerr as well, and see if it
+	// has the data at key name.
+	if cause, ok := x.cause.(*xerr); ok {
+		return GetData(cause, name)
+	}
+
+	return nil, false
+}
+
+// Data - returns a copy of the custom data of xerr and its causes. Values
+// stored on outer errors take precedence, like in GetData.
+// If err is not xerr or has no data then nil is returned
+func Data(err error) map[string]interface{} {
+	var data map[string]interface{}
+	for x, ok := err.(*xerr); ok; x, ok = x.cause.(*xerr) {
+		for name, value := range x.data {
+			if data == nil {
+				data = make(map[string]interface{})
+			}
+			if _, ok := data[name]; !ok {
+				data[name] = value
+			}
 		}
-		return nil, false
-	} else if x.data == nil && x.cause == nil {
-		return nil, false
 	}
 
-	v, ok := x.data[name]
-	return v, ok
+	return data
 }
 
 // SetData - sets custom data stored in xerr
@@ -179,9 +218,11 @@ func Stack(err error) []StackLocation {
 	return nil
 }
 
-// Details - returns a printable string which contains error, mask and stack
+// Details - returns a printable string which contains error, mask, severity,
+// creation time and goroutine when tracked, data merged from its xerr causes and stack
+// Sensitive data and registered scrubbers are applied to the output
 // maxStack can be supplied to change number of printer stack rows
-// If err is not xerr then err.Error() is returned
+// If err is not xerr then err.Error() with registered scrubbers applied is returned
 func Details(err error, maxStack int) string {
 	if err == nil {
 		return ""
@@ -189,17 +230,13 @@ func Details(err error, maxStack int) string {
 
 	const newLine = "\n"
 
-	result := []string{""}
 	x, ok := err.(*xerr)
 
 	if !ok {
-		return err.Error()
+		return Scrub(err.Error())
 	}
 
-	result = append(result, fmt.Sprintf("[ERROR] %s", x.cause.Error()))
-	if x.mask != nil && x.cause.Error() != x.mask.Error() {
-		result = append(result, fmt.Sprintf("[MASK ERROR] %s", x.mask.Error()))
-	}
+	result := append([]string{""}, detailsHeader(x)...)
 
 	if len(x.stack) == 0 {
 		return strings.Join(result, newLine)
@@ -219,6 +256,68 @@ func Details(err error, maxStack int) string {
 	return strings.Join(result, newLine)
 }
 
+// detailsHeader returns the lines of Details which precede the stack, with
+// sensitive data and registered scrubbers applied
+func detailsHeader(x *xerr) []string {
+	r := Record{
+		Cause:    Scrub(Internal(x.cause)),
+		Severity: SeverityOf(x),
+	}
+
+	if x.mask != nil && x.cause.Error() != x.mask.Error() {
+		r.Mask = Scrub(x.mask.Error())
+	}
+
+	if created := Time(x); !created.IsZero() {
+		r.Time = &created
+		r.Goroutine = Goroutine(x)
+	}
+
+	if data := Data(x); len(data) > 0 {
+		r.Data = make(map[string]interface{}, len(data))
+		for key, value := range data {
+			r.Data[key] = Scrub(fmt.Sprint(RedactValue(key, value)))
+		}
+	}
+
+	return r.detailsHeader()
+}
+
+// detailsHeader returns the lines of Details which precede the stack
+func (r Record) detailsHeader() []string {
+	var result []string
+
+	result = append(result, fmt.Sprintf("[ERROR] %s", r.Cause))
+	if r.Mask != "" {
+		result = append(result, fmt.Sprintf("[MASK ERROR] %s", r.Mask))
+	}
+
+	if r.Severity != SeverityUnset {
+		result = append(result, fmt.Sprintf("[SEVERITY] %s", r.Severity))
+	}
+
+	if r.Time != nil {
+		result = append(result, fmt.Sprintf("[TIME] %s", r.Time.Format(time.RFC3339Nano)))
+		result = append(result, fmt.Sprintf("[GOROUTINE] %d", r.Goroutine))
+	}
+
+	if len(r.Data) > 0 {
+		result = append(result, "[DATA]:")
+
+		keys := make([]string, 0, len(r.Data))
+		for key := range r.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			result = append(result, fmt.Sprintf("%s: %v", key, r.Data[key]))
+		}
+	}
+
+	return result
+}
+
 // Returns execution Stack of the goroutine which called it in the form of StackLocation array
 // skip - is a starting level on the execution stack where 0 = getStack() function itself, 1 = caller who called getStack(), and so forth
 func getStack(skip int) []StackLocation {
@@ -228,7 +327,7 @@ func getStack(skip int) []StackLocation {
 	for {
 		pc, fn, line, ok := runtime.Caller(skip + i)
 		if !ok {
-			return stack
+			return trimHelpers(stack)
 		}
 
 		newStackLocation := StackLocation{
@@ -244,31 +343,35 @@ func getStack(skip int) []StackLocation {
 }
 
 // Wrap returns an error annotated with a stack trace, and is prefixed with
-// the given message.
+// the given message. opts can annotate the error further, e.g.
+// Wrap(err, "load shipment", WithCode("db"), WithData("id", id)).
 // If err is nil, Wrap will return nil.
-func Wrap(err error, message string) error {
+func Wrap(err error, message string, opts ...Option) error {
 	if err == nil {
 		return nil
 	}
 
-	return &xerr{
-		stack: getStack(stackFunctionOffset),
+	return build(&xerr{
 		cause: err,
 		msg:   message,
-	}
+	}, opts)
 }
 
 // Wrapf returns an error annotated with a stack trace, and the given,
-// formatted message.
+// formatted message. Option values in args are applied to the error instead
+// of being formatted.
 // If err is nil, Wrapf will return nil.
 func Wrapf(err error, format string, args ...interface{}) error {
 	if err == nil {
 		return nil
 	}
 
-	return &xerr{
-		stack: getStack(stackFunctionOffset),
-		cause: err,
-		msg:   fmt.Sprintf(format, args...),
-	}
+	args, opts := splitOptions(args)
+
+	return build(&xerr{
+		cause:  err,
+		msg:    fmt.Sprintf(format, args...),
+		format: format,
+		args:   args,
+	}, opts)
 }
@@ -2,6 +2,7 @@ package xerrs
 
 import (
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -17,16 +18,6 @@ func getLastPathPart(str string) string {
 	return parts[len(parts)-1]
 }
 
-// Remove the path to the files in stack so that Testing would not be path dependent
-func transformStack(stack []StackLocation) []StackLocation {
-	for i := range stack {
-		stack[i].Function = getLastPathPart(stack[i].Function)
-		stack[i].File = getLastPathPart(stack[i].File)
-	}
-
-	return stack
-}
-
 func TestNew(t *testing.T) {
 	in := New("ABC")
 
@@ -187,6 +178,42 @@ func TestData(t *testing.T) {
 			t.Errorf("wanted foo=%q, got foo=%q", "bar", msg)
 		}
 	})
+
+	t.Run("nested error data with outer data", func(t *testing.T) {
+		a := New("a")
+		SetData(a, "foo", "bar")
+
+		b := Wrap(a, "b")
+		SetData(b, "baz", "qux")
+
+		if v, ok := GetData(b, "foo"); !ok || v != "bar" {
+			t.Errorf("wanted foo=%q, got foo=%v", "bar", v)
+		}
+		if v, ok := GetData(b, "baz"); !ok || v != "qux" {
+			t.Errorf("wanted baz=%q, got baz=%v", "qux", v)
+		}
+		if _, ok := GetData(b, "missing"); ok {
+			t.Error("expected false")
+		}
+	})
+
+	t.Run("all data", func(t *testing.T) {
+		a := New("a")
+		SetData(a, "foo", "bar")
+		SetData(a, "baz", "inner")
+
+		b := Wrap(a, "b")
+		SetData(b, "baz", "qux")
+
+		want := map[string]interface{}{"foo": "bar", "baz": "qux"}
+		if got := Data(b); !reflect.DeepEqual(got, want) {
+			t.Errorf("wrong data: want=%v got=%v", want, got)
+		}
+
+		if got := Data(New("c")); got != nil {
+			t.Errorf("wrong data: want=%v got=%v", nil, got)
+		}
+	})
 }
 
 func TestDetails(t *testing.T) {
@@ -195,7 +222,7 @@ func TestDetails(t *testing.T) {
 		InputError    error
 		InputMask     error
 		InputMaxStack int
-		OutputPrefix  string
+		Output        string
 	}
 
 	testCases := []TestCase{
@@ -204,45 +231,38 @@ func TestDetails(t *testing.T) {
 			InputError:    nil,
 			InputMask:     errors.New("MASK"),
 			InputMaxStack: 100,
-			OutputPrefix:  ``,
+			Output:        ``,
 		},
 		TestCase{
 			Description:   "basic",
 			InputError:    errors.New("ERROR"),
 			InputMask:     errors.New("MASK"),
 			InputMaxStack: 100,
-			OutputPrefix: `
+			Output: `
 [ERROR] ERROR
 [MASK ERROR] MASK
-[STACK]:`,
+[STACK]:
+github.com/RoseRocket/xerrs.TestDetails [xerrs_test.go:0]`,
 		},
 		TestCase{
 			Description:   "mask is the same as error",
 			InputError:    errors.New("ERROR"),
 			InputMask:     errors.New("ERROR"),
 			InputMaxStack: 100,
-			OutputPrefix: `
+			Output: `
 [ERROR] ERROR
-[STACK]:`,
+[STACK]:
+github.com/RoseRocket/xerrs.TestDetails [xerrs_test.go:0]`,
 		},
 		TestCase{
 			Description:   "mask is nil",
 			InputError:    errors.New("ERROR"),
 			InputMask:     nil,
 			InputMaxStack: 100,
-			OutputPrefix: `
+			Output: `
 [ERROR] ERROR
-[STACK]:`,
-		},
-		TestCase{
-			Description:   "fewer stack lines",
-			InputError:    errors.New("ERROR"),
-			InputMask:     errors.New("MASK"),
-			InputMaxStack: 4,
-			OutputPrefix: `
-[ERROR] ERROR
-[MASK ERROR] MASK
-[STACK]:`,
+[STACK]:
+github.com/RoseRocket/xerrs.TestDetails [xerrs_test.go:0]`,
 		},
 	}
 
@@ -256,14 +276,42 @@ func TestDetails(t *testing.T) {
 		}
 
 		if x, ok := err.(*xerr); ok {
-			x.stack = transformStack(x.stack)
+			x.stack = NormalizeStack(x.stack)
 		}
 
-		if !strings.HasPrefix(Details(err, testCase.InputMaxStack), testCase.OutputPrefix) {
-			t.Errorf("wrong output prefix: wanted prefix=%v got=%v", testCase.OutputPrefix, Details(err, testCase.InputMaxStack))
+		if got := Details(err, testCase.InputMaxStack); got != testCase.Output {
+			t.Errorf("wrong output for %s: want=%v got=%v", testCase.Description, testCase.Output, got)
 		}
 	}
 
+	err := Mask(errors.New("ERROR"), errors.New("MASK"))
+	stack := Stack(err)
+	if len(stack) <= 2 {
+		t.Fatalf("expected a stack deeper than 2, got=%v", stack)
+	}
+	wantTruncated := strings.Join([]string{"", "[ERROR] ERROR", "[MASK ERROR] MASK", "[STACK]:", stack[0].String(), stack[1].String()}, "\n")
+	if got := Details(err, 2); got != wantTruncated {
+		t.Errorf("wrong output for fewer stack lines: want=%v got=%v", wantTruncated, got)
+	}
+
+	err = Extend(errors.New("ERROR"))
+	SetData(err, "b", 2)
+	SetData(err, "a", "one")
+	wantPrefix := `
+[ERROR] ERROR
+[DATA]:
+a: one
+b: 2
+[STACK]:`
+	if got := Details(err, 5); !strings.HasPrefix(got, wantPrefix) {
+		t.Errorf("wrong output prefix: wanted prefix=%v got=%v", wantPrefix, got)
+	}
+
+	err = Wrap(New("connection refused", WithData("host", "db1")), "load", WithData("id", 7))
+	if got := Details(err, 5); !strings.Contains(got, "[DATA]:\nhost: db1\nid: 7\n") {
+		t.Errorf("wrong data of wrapped error: want=%v got=%v", "host: db1, id: 7", got)
+	}
+
 	if Details(errors.New("ABC"), 5) != "ABC" {
 		t.Errorf("running stack on the basic go error failed: want=%v got=%v", "ABC", Details(errors.New("ABC"), 5))
 	}
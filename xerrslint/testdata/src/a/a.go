@@ -0,0 +1,116 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+
+	"b"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+const keyID = "id"
+
+var errLocal = xerrs.New("local")
+
+func unwrappedCall() error {
+	return b.Save() // want `error returned from b.Save is not extended, use xerrs.Extend or xerrs.Wrap`
+}
+
+func unwrappedTuple() (string, error) {
+	return b.Load() // want `error returned from b.Load is not extended`
+}
+
+func unwrappedVariable() (string, error) {
+	v, err := b.Load()
+	if err != nil {
+		return "", err // want `error returned from b.Load is not extended`
+	}
+	return v, nil
+}
+
+func unwrappedIf() error {
+	if err := b.Save(); err != nil {
+		return err // want `error returned from b.Save is not extended`
+	}
+	return nil
+}
+
+func wrapped() error {
+	_, err := b.Load()
+	if err != nil {
+		return xerrs.Wrap(err, "load")
+	}
+
+	err = b.Save()
+	if err != nil {
+		err = xerrs.Extend(err)
+		return err
+	}
+
+	return nil
+}
+
+func local() error {
+	if err := wrapped(); err != nil {
+		return err
+	}
+	if true {
+		return errors.New("new")
+	}
+	return fmt.Errorf("formatted")
+}
+
+func literal() func() error {
+	return func() error {
+		err := b.Save()
+		return err // want `error returned from b.Save is not extended`
+	}
+}
+
+func wrapTwice() error {
+	err := wrapped()
+	err = xerrs.Wrap(err, "first")
+	return xerrs.Wrapf(err, "second %d", 2) // want `error is wrapped twice in the same function`
+}
+
+func wrapNested() error {
+	return xerrs.Wrap(xerrs.Wrap(wrapped(), "first"), "second") // want `error is wrapped twice in the same function`
+}
+
+func wrapBranches(ok bool) error {
+	err := wrapped()
+	if ok {
+		return xerrs.Wrap(err, "ok")
+	}
+	return xerrs.Wrap(err, "not ok")
+}
+
+func mask() error {
+	_ = xerrs.Mask(errLocal, errors.New("mask"))      // want `xerrs.Mask modifies the package-level error errLocal`
+	_ = xerrs.Mask(b.ErrNotFound, errors.New("mask")) // want `xerrs.Mask modifies the package-level error ErrNotFound`
+	return xerrs.Mask(xerrs.Extend(errLocal), errors.New("mask"))
+}
+
+func setData(err error) {
+	xerrs.SetData(err, "id", 1) // want `xerrs.SetData key "id" should be declared as a constant`
+	xerrs.SetData(err, keyID, 1)
+}
+
+func compare(err error) bool {
+	if err == nil || nil != err {
+		return false
+	}
+	if err == errLocal { // want `errors compared with ==, use errors.Is or xerrs.IsEqual`
+		return true
+	}
+	return b.ErrNotFound != err // want `errors compared with !=`
+}
+
+type sentinel struct{ target error }
+
+func (s sentinel) Error() string { return "sentinel" }
+
+func (s sentinel) Is(target error) bool {
+	return s.target == target
+}
@@ -0,0 +1,9 @@
+package b
+
+import "errors"
+
+var ErrNotFound = errors.New("not found")
+
+func Load() (string, error) { return "", ErrNotFound }
+
+func Save() error { return nil }
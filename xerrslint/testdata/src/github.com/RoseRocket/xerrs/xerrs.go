@@ -0,0 +1,11 @@
+// Package xerrs is a stub of github.com/RoseRocket/xerrs for analyzer tests.
+package xerrs
+
+func New(message string) error                                  { return nil }
+func Errorf(format string, args ...interface{}) error           { return nil }
+func Extend(err error) error                                    { return err }
+func Wrap(err error, message string) error                      { return err }
+func Wrapf(err error, format string, args ...interface{}) error { return err }
+func Mask(err, mask error) error                                { return err }
+func SetData(err error, name string, value interface{})         {}
+func IsEqual(err1, err2 error) bool                             { return false }
@@ -0,0 +1,307 @@
+// Package xerrslint defines an analyzer which reports common mistakes made
+// with the xerrs package:
+//
+//   - errors returned from other packages without xerrs.Extend or xerrs.Wrap,
+//     so they carry no stack
+//   - xerrs.Wrap or xerrs.Wrapf applied twice to the same error in one function
+//   - xerrs.Mask called on package-level sentinel errors, which mutates them
+//   - xerrs.SetData keys written as string literals instead of constants
+//   - errors compared with == or != instead of errors.Is or xerrs.IsEqual,
+//     except in Is methods
+//
+// Errors created with the errors and fmt packages are not reported as
+// returned from other packages.
+package xerrslint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const xerrsPath = "github.com/RoseRocket/xerrs"
+
+// Analyzer - reports misuse of the xerrs package
+var Analyzer = &analysis.Analyzer{
+	Name:     "xerrslint",
+	Doc:      "report misuse of the github.com/RoseRocket/xerrs package",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// assignment records that obj was assigned the result of call by a statement ending at pos
+type assignment struct {
+	obj  types.Object
+	pos  token.Pos
+	call *ast.CallExpr
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	in := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	in.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			if fn.Body != nil {
+				checkFunc(pass, fn.Body)
+			}
+		case *ast.FuncLit:
+			checkFunc(pass, fn.Body)
+		}
+	})
+
+	in.WithStack([]ast.Node{(*ast.CallExpr)(nil), (*ast.BinaryExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		switch expr := n.(type) {
+		case *ast.CallExpr:
+			checkMask(pass, expr)
+			checkSetData(pass, expr)
+		case *ast.BinaryExpr:
+			if !inIsMethod(stack) {
+				checkComparison(pass, expr)
+			}
+		}
+		return true
+	})
+
+	return nil, nil
+}
+
+// checkFunc reports unwrapped external errors and double wrapping in one
+// function body. Nested function literals are checked on their own.
+func checkFunc(pass *analysis.Pass, body *ast.BlockStmt) {
+	var assignments []assignment
+	var returns []*ast.ReturnStmt
+	var wraps []*ast.CallExpr
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			assignments = append(assignments, assignmentsOf(pass, node.Lhs, node.Rhs, node.End())...)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(node.Names))
+			for i, name := range node.Names {
+				lhs[i] = name
+			}
+			assignments = append(assignments, assignmentsOf(pass, lhs, node.Values, node.End())...)
+		case *ast.ReturnStmt:
+			returns = append(returns, node)
+		case *ast.CallExpr:
+			if isXerrsCall(pass, node, "Wrap", "Wrapf") {
+				wraps = append(wraps, node)
+			}
+		}
+		return true
+	})
+
+	// lastCall returns the call whose result was last assigned to ident before pos
+	lastCall := func(expr ast.Expr, pos token.Pos) *ast.CallExpr {
+		ident, ok := astutil.Unparen(expr).(*ast.Ident)
+		if !ok {
+			return nil
+		}
+
+		obj := pass.TypesInfo.ObjectOf(ident)
+		if obj == nil {
+			return nil
+		}
+
+		var last *ast.CallExpr
+		for _, a := range assignments {
+			if a.obj == obj && a.pos < pos {
+				last = a.call
+			}
+		}
+		return last
+	}
+
+	for _, ret := range returns {
+		for _, result := range ret.Results {
+			if !isError(pass.TypesInfo.TypeOf(result)) && !returnsError(pass, result) {
+				continue
+			}
+
+			call, ok := astutil.Unparen(result).(*ast.CallExpr)
+			if !ok {
+				call = lastCall(result, ret.Pos())
+			}
+
+			if fn := externalCallee(pass, call); fn != nil {
+				pass.Reportf(result.Pos(), "error returned from %s.%s is not extended, use xerrs.Extend or xerrs.Wrap", fn.Pkg().Name(), fn.Name())
+			}
+		}
+	}
+
+	for _, wrap := range wraps {
+		if len(wrap.Args) == 0 {
+			continue
+		}
+
+		inner, ok := astutil.Unparen(wrap.Args[0]).(*ast.CallExpr)
+		if !ok {
+			inner = lastCall(wrap.Args[0], wrap.Pos())
+		}
+
+		if inner != nil && isXerrsCall(pass, inner, "Wrap", "Wrapf") {
+			pass.Reportf(wrap.Pos(), "error is wrapped twice in the same function, combine the messages into one xerrs.Wrap")
+		}
+	}
+}
+
+// assignmentsOf returns the calls assigned to identifiers in lhs by a statement ending at end
+func assignmentsOf(pass *analysis.Pass, lhs, rhs []ast.Expr, end token.Pos) []assignment {
+	var result []assignment
+
+	for i, expr := range lhs {
+		ident, ok := expr.(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			continue
+		}
+
+		obj := pass.TypesInfo.ObjectOf(ident)
+		if obj == nil {
+			continue
+		}
+
+		var value ast.Expr
+		switch {
+		case len(rhs) == len(lhs):
+			value = rhs[i]
+		case len(rhs) == 1:
+			// a, err := f()
+			value = rhs[0]
+		default:
+			continue
+		}
+
+		call, _ := astutil.Unparen(value).(*ast.CallExpr)
+		result = append(result, assignment{obj: obj, pos: end, call: call})
+	}
+
+	return result
+}
+
+// checkMask reports xerrs.Mask called on a package-level variable
+func checkMask(pass *analysis.Pass, call *ast.CallExpr) {
+	if !isXerrsCall(pass, call, "Mask") || len(call.Args) == 0 {
+		return
+	}
+
+	var ident *ast.Ident
+	switch arg := astutil.Unparen(call.Args[0]).(type) {
+	case *ast.Ident:
+		ident = arg
+	case *ast.SelectorExpr:
+		ident = arg.Sel
+	default:
+		return
+	}
+
+	v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return
+	}
+
+	pass.Reportf(call.Pos(), "xerrs.Mask modifies the package-level error %s, mask a new error created with xerrs.Extend instead", ident.Name)
+}
+
+// checkSetData reports xerrs.SetData called with a string literal key
+func checkSetData(pass *analysis.Pass, call *ast.CallExpr) {
+	if !isXerrsCall(pass, call, "SetData") || len(call.Args) < 2 {
+		return
+	}
+
+	if lit, ok := astutil.Unparen(call.Args[1]).(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		pass.Reportf(lit.Pos(), "xerrs.SetData key %s should be declared as a constant", lit.Value)
+	}
+}
+
+// checkComparison reports errors compared with == or !=
+func checkComparison(pass *analysis.Pass, expr *ast.BinaryExpr) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+
+	x, y := pass.TypesInfo.Types[expr.X], pass.TypesInfo.Types[expr.Y]
+	if x.IsNil() || y.IsNil() {
+		return
+	}
+
+	if !isError(x.Type) && !isError(y.Type) {
+		return
+	}
+
+	pass.Reportf(expr.OpPos, "errors compared with %s, use errors.Is or xerrs.IsEqual", expr.Op)
+}
+
+// inIsMethod reports whether the innermost function declaration in stack is an
+// Is method, which compares errors with == by design
+func inIsMethod(stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if fn, ok := stack[i].(*ast.FuncDecl); ok {
+			return fn.Recv != nil && fn.Name.Name == "Is"
+		}
+	}
+
+	return false
+}
+
+// isXerrsCall reports whether call calls one of the named xerrs functions
+func isXerrsCall(pass *analysis.Pass, call *ast.CallExpr, names ...string) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != xerrsPath {
+		return false
+	}
+
+	for _, name := range names {
+		if fn.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// externalCallee returns the function called by call if it belongs to another
+// package which is not xerrs, errors or fmt
+func externalCallee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	if call == nil {
+		return nil
+	}
+
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg() == pass.Pkg {
+		return nil
+	}
+
+	switch fn.Pkg().Path() {
+	case xerrsPath, "errors", "fmt":
+		return nil
+	}
+
+	return fn
+}
+
+// isError reports whether t is the error interface
+func isError(t types.Type) bool {
+	return t != nil && types.Identical(t, errorType)
+}
+
+// returnsError reports whether expr is a call whose last result is an error
+func returnsError(pass *analysis.Pass, expr ast.Expr) bool {
+	tuple, ok := pass.TypesInfo.TypeOf(expr).(*types.Tuple)
+	return ok && tuple.Len() > 0 && isError(tuple.At(tuple.Len()-1).Type())
+}
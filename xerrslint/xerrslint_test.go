@@ -0,0 +1,12 @@
+package xerrslint_test
+
+import (
+	"testing"
+
+	"github.com/RoseRocket/xerrs/xerrslint"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), xerrslint.Analyzer, "a")
+}
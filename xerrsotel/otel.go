@@ -0,0 +1,131 @@
+// Package xerrsotel records xerrs errors on OpenTelemetry spans following the
+// semantic conventions for exceptions.
+package xerrsotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/RoseRocket/xerrs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Data keys set by AttachTraceContext
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// Record - records err on span as an exception event and sets the span status
+// to Error with the public message of err as its description, see xerrs.Public
+// If err is nil then nothing happens
+func Record(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	RecordError(span, err)
+	span.SetStatus(codes.Error, xerrs.Public(err))
+}
+
+// RecordError - adds an "exception" event to span with exception.type,
+// exception.message and exception.stacktrace built from the error, and the
+// attributes returned by Attributes
+// If err is nil then nothing happens
+func RecordError(span trace.Span, err error, opts ...trace.EventOption) {
+	if err == nil {
+		return
+	}
+
+	attributes := []attribute.KeyValue{
+		attribute.String("exception.type", exceptionType(err)),
+		attribute.String("exception.message", xerrs.Scrub(xerrs.Internal(err))),
+	}
+	if stack := xerrs.Stack(err); len(stack) > 0 {
+		attributes = append(attributes, attribute.String("exception.stacktrace", stacktrace(stack)))
+	}
+	attributes = append(attributes, Attributes(err)...)
+
+	span.AddEvent("exception", append([]trace.EventOption{trace.WithAttributes(attributes...)}, opts...)...)
+}
+
+// Attributes - returns the code, severity and fingerprint of err and its data
+// as span attributes. Data keys are prefixed with "xerrs.data.".
+// Sensitive data and registered scrubbers are applied to the values
+func Attributes(err error) []attribute.KeyValue {
+	if err == nil {
+		return nil
+	}
+
+	var attributes []attribute.KeyValue
+	if code := xerrs.Code(err); code != "" {
+		attributes = append(attributes, attribute.String("xerrs.code", code))
+	}
+	if severity := xerrs.SeverityOf(err); severity != xerrs.SeverityUnset {
+		attributes = append(attributes, attribute.String("xerrs.severity", severity.String()))
+	}
+	attributes = append(attributes, attribute.String("xerrs.fingerprint", xerrs.Fingerprint(err)))
+
+	for name, value := range xerrs.Data(err) {
+		attributes = append(attributes, dataAttribute("xerrs.data."+name, xerrs.RedactValue(name, value)))
+	}
+
+	return attributes
+}
+
+// AttachTraceContext - stores the trace and span IDs of the span in ctx in the
+// data of err, so logs of the error can be joined with the trace
+// If err is not xerr or ctx has no valid span then nothing happens
+func AttachTraceContext(ctx context.Context, err error) {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return
+	}
+
+	xerrs.SetData(err, TraceIDKey, spanContext.TraceID().String())
+	xerrs.SetData(err, SpanIDKey, spanContext.SpanID().String())
+}
+
+// exceptionType returns the code of err, or the type of its root cause
+func exceptionType(err error) string {
+	if code := xerrs.Code(err); code != "" {
+		return code
+	}
+
+	for next := errors.Unwrap(err); next != nil; next = errors.Unwrap(err) {
+		err = next
+	}
+
+	return fmt.Sprintf("%T", err)
+}
+
+// stacktrace formats stack like a goroutine trace printed by the runtime
+func stacktrace(stack []xerrs.StackLocation) string {
+	var b strings.Builder
+	for _, location := range stack {
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", location.Function, location.File, location.Line)
+	}
+
+	return b.String()
+}
+
+func dataAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	}
+
+	return attribute.String(key, xerrs.Scrub(fmt.Sprint(value)))
+}
@@ -0,0 +1,110 @@
+package xerrsotel
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracer() (*tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	exporter := tracetest.NewInMemoryExporter()
+	return exporter, sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+}
+
+func TestRecord(t *testing.T) {
+	exporter, provider := newTracer()
+
+	defer xerrs.ResetRedaction()
+	xerrs.MarkSensitive("token")
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "load")
+
+	err := xerrs.New("shipment 7 not found",
+		xerrs.WithCode("not_found"),
+		xerrs.WithSeverity(xerrs.SeverityInfo),
+		xerrs.WithMask(errors.New("shipment not found")),
+		xerrs.WithData("shipment_id", 7),
+		xerrs.WithData("token", "secret"))
+	AttachTraceContext(ctx, err)
+
+	Record(span, xerrs.Wrap(err, "load shipment"))
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("wrong number of spans: want=%v got=%v", 1, len(spans))
+	}
+
+	got := spans[0]
+	if got.Status.Code != codes.Error || got.Status.Description != "shipment not found" {
+		t.Errorf("wrong status: want=%v got=%v", "Error shipment not found", got.Status)
+	}
+
+	if len(got.Events) != 1 || got.Events[0].Name != "exception" {
+		t.Fatalf("wrong events: %v", got.Events)
+	}
+
+	attributes := attribute.NewSet(got.Events[0].Attributes...)
+	want := map[attribute.Key]attribute.Value{
+		"exception.type":           attribute.StringValue("not_found"),
+		"exception.message":        attribute.StringValue("load shipment: shipment 7 not found"),
+		"xerrs.code":               attribute.StringValue("not_found"),
+		"xerrs.severity":           attribute.StringValue("info"),
+		"xerrs.data.shipment_id":   attribute.IntValue(7),
+		"xerrs.data.token":         attribute.StringValue(xerrs.RedactedText),
+		"xerrs.data." + TraceIDKey: attribute.StringValue(got.SpanContext.TraceID().String()),
+		"xerrs.data." + SpanIDKey:  attribute.StringValue(got.SpanContext.SpanID().String()),
+	}
+	for key, value := range want {
+		if v, ok := attributes.Value(key); !ok || v != value {
+			t.Errorf("wrong attribute %s: want=%v got=%v", key, value.Emit(), v.Emit())
+		}
+	}
+
+	stacktrace, _ := attributes.Value("exception.stacktrace")
+	if !strings.HasPrefix(stacktrace.AsString(), "github.com/RoseRocket/xerrs/xerrsotel.TestRecord\n\t") {
+		t.Errorf("wrong stacktrace: %v", stacktrace.AsString())
+	}
+}
+
+func TestRecordPlainError(t *testing.T) {
+	exporter, provider := newTracer()
+
+	_, span := provider.Tracer("test").Start(context.Background(), "load")
+	Record(span, errors.New("boom"))
+	Record(span, nil)
+	span.End()
+
+	got := exporter.GetSpans()[0]
+	if got.Status.Description != xerrs.DefaultPublicMessage {
+		t.Errorf("wrong status description: want=%v got=%v", xerrs.DefaultPublicMessage, got.Status.Description)
+	}
+
+	if len(got.Events) != 1 {
+		t.Fatalf("wrong number of events: want=%v got=%v", 1, len(got.Events))
+	}
+
+	attributes := attribute.NewSet(got.Events[0].Attributes...)
+	if v, _ := attributes.Value("exception.type"); v.AsString() != "*errors.errorString" {
+		t.Errorf("wrong exception type: want=%v got=%v", "*errors.errorString", v.AsString())
+	}
+	if attributes.HasValue("exception.stacktrace") {
+		t.Errorf("expected no stacktrace for an error without stack")
+	}
+}
+
+func TestAttachTraceContextWithoutSpan(t *testing.T) {
+	err := xerrs.New("boom")
+	AttachTraceContext(context.Background(), err)
+
+	if _, ok := xerrs.GetData(err, TraceIDKey); ok {
+		t.Errorf("expected no trace ID without a span")
+	}
+}
@@ -0,0 +1,312 @@
+// Package xerrssentry converts xerrs errors to Sentry events and sends them to
+// a Sentry compatible server.
+package xerrssentry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+// Event - the subset of the Sentry event payload filled by NewEvent
+type Event struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   time.Time              `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Platform    string                 `json:"platform"`
+	Release     string                 `json:"release,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Exception   Exceptions             `json:"exception"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Fingerprint []string               `json:"fingerprint,omitempty"`
+}
+
+// Exceptions - the exception interface of an event. Values are ordered from
+// the root cause to the outermost error, as Sentry expects.
+type Exceptions struct {
+	Values []Exception `json:"values"`
+}
+
+// Exception - one layer of the error chain
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace - frames of an exception, oldest call first
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Frame - one stack location
+type Frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// Options - controls NewEvent
+type Options struct {
+	// InAppPrefixes selects application frames by package path prefix. If empty
+	// then every frame outside the Go standard library and dependencies is in app.
+	InAppPrefixes []string
+	Release       string
+	Environment   string
+	ServerName    string
+	// Tags are added to every event.
+	Tags map[string]string
+}
+
+var levels = map[xerrs.Severity]string{
+	xerrs.SeverityUnset:    "error",
+	xerrs.SeverityDebug:    "debug",
+	xerrs.SeverityInfo:     "info",
+	xerrs.SeverityWarning:  "warning",
+	xerrs.SeverityError:    "error",
+	xerrs.SeverityCritical: "fatal",
+}
+
+// NewEvent - converts err to a Sentry event with one exception per layer of the
+// chain which adds a stack or a message, typed by the code set on the layer or
+// its Go type and with its own message only, tags for the code and severity, the
+// data of the chain as extra and the xerrs fingerprint
+// Sensitive data and registered scrubbers are applied to the event
+// If err is nil then nil is returned
+func NewEvent(err error, opts Options) *Event {
+	if err == nil {
+		return nil
+	}
+
+	event := &Event{
+		EventID:     newEventID(),
+		Timestamp:   xerrs.Time(err),
+		Level:       levels[xerrs.SeverityOf(err)],
+		Platform:    "go",
+		Release:     opts.Release,
+		Environment: opts.Environment,
+		ServerName:  opts.ServerName,
+		Tags:        make(map[string]string, len(opts.Tags)+2),
+		Fingerprint: []string{xerrs.Fingerprint(err)},
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if event.Level == "" {
+		event.Level = "error"
+	}
+
+	for name, value := range opts.Tags {
+		event.Tags[name] = value
+	}
+	if code := xerrs.Code(err); code != "" {
+		event.Tags["code"] = code
+	}
+	if severity := xerrs.SeverityOf(err); severity != xerrs.SeverityUnset {
+		event.Tags["severity"] = severity.String()
+	}
+
+	for name, value := range xerrs.Data(err) {
+		if event.Extra == nil {
+			event.Extra = make(map[string]interface{})
+		}
+		event.Extra[name] = xerrs.RedactValue(name, value)
+	}
+
+	event.Exception.Values = exceptions(err, opts)
+	return event
+}
+
+// exceptions returns the layers of the chain, root cause first
+func exceptions(err error, opts Options) []Exception {
+	var result []Exception
+	previous := ""
+	for ; err != nil; err = errors.Unwrap(err) {
+		stack := xerrs.Stack(err)
+		message := xerrs.Scrub(ownMessage(err))
+		if len(stack) == 0 && message == previous {
+			continue
+		}
+		previous = message
+
+		exception := Exception{
+			Type:  layerType(err),
+			Value: message,
+		}
+		if len(stack) > 0 {
+			exception.Stacktrace = &Stacktrace{Frames: frames(stack, opts)}
+		}
+
+		result = append([]Exception{exception}, result...)
+	}
+
+	return result
+}
+
+// ownMessage returns the message err adds to the error it wraps, e.g. the
+// annotation of Wrap. Layers which add no message return the wrapped message.
+func ownMessage(err error) string {
+	message := xerrs.Internal(err)
+	if next := errors.Unwrap(err); next != nil {
+		message = strings.TrimSuffix(message, ": "+xerrs.Internal(next))
+	}
+
+	return message
+}
+
+// layerType returns the code set on err itself, or its Go type. Layers which
+// add no message, like Extend, have the type of the error they wrap.
+func layerType(err error) string {
+	next := errors.Unwrap(err)
+	if code := xerrs.Code(err); code != "" && code != xerrs.Code(next) {
+		return code
+	}
+
+	if next != nil && xerrs.Internal(err) == xerrs.Internal(next) {
+		return layerType(next)
+	}
+
+	return fmt.Sprintf("%T", err)
+}
+
+// frames converts stack to Sentry frames, oldest call first
+func frames(stack []xerrs.StackLocation, opts Options) []Frame {
+	result := make([]Frame, len(stack))
+	for i, location := range stack {
+		module, function := splitFunction(location.Function)
+		result[len(stack)-1-i] = Frame{
+			Function: function,
+			Module:   module,
+			Filename: location.File,
+			AbsPath:  location.File,
+			Lineno:   location.Line,
+			InApp:    opts.inApp(module, location.File),
+		}
+	}
+
+	return result
+}
+
+// splitFunction splits a fully qualified function name into its package path
+// and the function name, e.g. "github.com/a/b.(*T).Do" into "github.com/a/b"
+// and "(*T).Do"
+func splitFunction(name string) (module, function string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+
+	dot += slash + 1
+	return name[:dot], name[dot+1:]
+}
+
+func (opts Options) inApp(module, file string) bool {
+	if len(opts.InAppPrefixes) > 0 {
+		for _, prefix := range opts.InAppPrefixes {
+			if strings.HasPrefix(module, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	file = strings.ReplaceAll(file, "\\", "/")
+	if strings.Contains(file, "/vendor/") || strings.Contains(file, "/pkg/mod/") {
+		return false
+	}
+
+	// standard library import paths have no dot in the first element
+	first := strings.SplitN(module, "/", 2)[0]
+	return first == "main" || strings.Contains(first, ".")
+}
+
+func newEventID() string {
+	var id [16]byte
+	rand.Read(id[:])
+
+	return hex.EncodeToString(id[:])
+}
+
+// Transport - sends events to the store endpoint of a Sentry compatible server
+type Transport struct {
+	// Client is used to send events. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	endpoint  string
+	publicKey string
+}
+
+// NewTransport - returns a Transport for a DSN such as
+// https://<public key>@sentry.example.com/<project id>
+func NewTransport(dsn string) (*Transport, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, xerrs.Extend(err)
+	}
+
+	project := strings.Trim(u.Path, "/")
+	if u.User == nil || u.User.Username() == "" || project == "" || u.Host == "" {
+		return nil, xerrs.Errorf("invalid DSN %q", dsn)
+	}
+
+	path := ""
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		path, project = "/"+project[:i], project[i+1:]
+	}
+
+	return &Transport{
+		endpoint:  fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, path, project),
+		publicKey: u.User.Username(),
+	}, nil
+}
+
+// Send - sends event and waits for the server to accept it
+func (t *Transport) Send(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return xerrs.Extend(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return xerrs.Extend(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=xerrs, sentry_key=%s", t.publicKey))
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return xerrs.Extend(err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return xerrs.Errorf("sentry: unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
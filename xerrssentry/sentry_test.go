@@ -0,0 +1,158 @@
+package xerrssentry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+func TestNewEvent(t *testing.T) {
+	defer xerrs.ResetRedaction()
+	xerrs.MarkSensitive("token")
+
+	err := xerrs.New("shipment 7 not found",
+		xerrs.WithCode("not_found"),
+		xerrs.WithSeverity(xerrs.SeverityWarning),
+		xerrs.WithData("shipment_id", 7),
+		xerrs.WithData("retried", true),
+		xerrs.WithData("token", "secret"),
+	)
+	err = xerrs.Wrap(err, "load shipment")
+
+	event := NewEvent(err, Options{Release: "1.2.3", Tags: map[string]string{"service": "api"}})
+
+	if event.Level != "warning" {
+		t.Errorf("wrong level: want=%v got=%v", "warning", event.Level)
+	}
+
+	wantTags := map[string]string{"service": "api", "code": "not_found", "severity": "warning"}
+	if !reflect.DeepEqual(event.Tags, wantTags) {
+		t.Errorf("wrong tags: want=%v got=%v", wantTags, event.Tags)
+	}
+
+	wantExtra := map[string]interface{}{"shipment_id": 7, "retried": true, "token": xerrs.RedactedText}
+	if !reflect.DeepEqual(event.Extra, wantExtra) {
+		t.Errorf("wrong extra: want=%v got=%v", wantExtra, event.Extra)
+	}
+
+	if len(event.Fingerprint) != 1 || event.Fingerprint[0] != xerrs.Fingerprint(err) {
+		t.Errorf("wrong fingerprint: want=%v got=%v", xerrs.Fingerprint(err), event.Fingerprint)
+	}
+
+	if len(event.EventID) != 32 || event.Release != "1.2.3" || event.Platform != "go" {
+		t.Errorf("wrong event header: %+v", event)
+	}
+
+	values := event.Exception.Values
+	if len(values) != 2 {
+		t.Fatalf("wrong number of exceptions: want=%v got=%v", 2, len(values))
+	}
+
+	if values[0].Value != "shipment 7 not found" || values[1].Value != "load shipment" {
+		t.Errorf("wrong exception values: %+v", values)
+	}
+	if values[0].Type != "not_found" || values[1].Type != "*xerrs.xerr" {
+		t.Errorf("wrong exception types: want=%v got=%v", []string{"not_found", "*xerrs.xerr"}, []string{values[0].Type, values[1].Type})
+	}
+
+	frames := values[1].Stacktrace.Frames
+	last := frames[len(frames)-1]
+	if last.Function != "TestNewEvent" || last.Module != "github.com/RoseRocket/xerrs/xerrssentry" || !last.InApp {
+		t.Errorf("wrong newest frame: %+v", last)
+	}
+	if first := frames[0]; first.InApp {
+		t.Errorf("expected oldest frame %v not to be in app", first.Function)
+	}
+
+	if event := NewEvent(nil, Options{}); event != nil {
+		t.Errorf("wrong event for nil: want=%v got=%v", nil, event)
+	}
+}
+
+func TestExceptions(t *testing.T) {
+	err := fmt.Errorf("sync rates: %w", xerrs.New("connection refused"))
+
+	var got []string
+	for _, exception := range exceptions(err, Options{}) {
+		got = append(got, exception.Type+" "+exception.Value)
+	}
+
+	want := []string{"*errors.errorString connection refused", "*fmt.wrapError sync rates"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong exceptions: want=%v got=%v", want, got)
+	}
+}
+
+func TestInApp(t *testing.T) {
+	cases := []struct {
+		module   string
+		file     string
+		prefixes []string
+		want     bool
+	}{
+		{"main", "/app/main.go", nil, true},
+		{"example.com/app", "/app/app.go", nil, true},
+		{"runtime", "/go/src/runtime/proc.go", nil, false},
+		{"example.com/lib", "/app/vendor/example.com/lib/lib.go", nil, false},
+		{"example.com/lib", "/root/go/pkg/mod/example.com/lib@v1.0.0/lib.go", nil, false},
+		{"example.com/lib", "/app/lib.go", []string{"example.com/app"}, false},
+		{"example.com/app/db", "/app/db/db.go", []string{"example.com/app"}, true},
+	}
+
+	for _, c := range cases {
+		if got := (Options{InAppPrefixes: c.prefixes}).inApp(c.module, c.file); got != c.want {
+			t.Errorf("wrong in app for %s: want=%v got=%v", c.module, c.want, got)
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var auth string
+	var event Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/42/store/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		auth = r.Header.Get("X-Sentry-Auth")
+		json.NewDecoder(r.Body).Decode(&event)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://public@", 1) + "/42"
+	transport, err := NewTransport(dsn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sent := NewEvent(xerrs.New("boom"), Options{})
+	if err := transport.Send(context.Background(), sent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(auth, "sentry_key=public") {
+		t.Errorf("wrong auth header: %v", auth)
+	}
+	if event.EventID != sent.EventID || event.Exception.Values[0].Value != "boom" {
+		t.Errorf("wrong event received: %+v", event)
+	}
+
+	transport, _ = NewTransport(strings.Replace(server.URL, "://", "://public@", 1) + "/prefix/1")
+	if err := transport.Send(context.Background(), sent); err == nil {
+		t.Errorf("expected an error for status 404")
+	}
+
+	for _, dsn := range []string{"https://sentry.example.com/1", "https://key@sentry.example.com/", "%"} {
+		if _, err := NewTransport(dsn); err == nil {
+			t.Errorf("expected an error for DSN %q", dsn)
+		}
+	}
+}
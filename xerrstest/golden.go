@@ -0,0 +1,110 @@
+package xerrstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+// update is set with go test -xerrstest.update to rewrite golden files with the
+// current output. The name is qualified so that it does not clash with an
+// -update flag of the package under test.
+var update = flag.Bool("xerrstest.update", false, "update golden files of xerrstest.AssertGolden")
+
+// AssertGolden - checks that got is equal to the content of the golden file at
+// path, e.g. "testdata/not_found.golden". Running go test -xerrstest.update
+// writes got to the file instead.
+func AssertGolden(tb testing.TB, path string, got []byte) bool {
+	tb.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			tb.Fatalf("create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			tb.Fatalf("write golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("missing golden file, run go test -xerrstest.update: %v", err)
+		return false
+	}
+
+	if !bytes.Equal(got, want) {
+		tb.Errorf("output does not match %s, run go test -xerrstest.update to accept:\n%s", path, lineDiff(string(want), string(got)))
+		return false
+	}
+
+	return true
+}
+
+// AssertGoldenDetails - checks the normalized Details of err against the golden
+// file at path, see AssertGolden and xerrs.NormalizeDetails
+func AssertGoldenDetails(tb testing.TB, path string, err error) bool {
+	tb.Helper()
+
+	details := strings.TrimPrefix(xerrs.Details(err, MaxStack), "\n")
+	return AssertGolden(tb, path, []byte(xerrs.NormalizeDetails(details)+"\n"))
+}
+
+// AssertGoldenJSON - checks the normalized and indented JSON encoding of err
+// against the golden file at path, see AssertGolden and xerrs.NormalizeJSON
+func AssertGoldenJSON(tb testing.TB, path string, err error) bool {
+	tb.Helper()
+
+	encoded, e := json.Marshal(err)
+	if e != nil {
+		tb.Fatalf("encode error: %v", e)
+	}
+
+	normalized, e := xerrs.NormalizeJSON(encoded)
+	if e != nil {
+		tb.Fatalf("normalize error: %v", e)
+	}
+
+	var indented bytes.Buffer
+	json.Indent(&indented, normalized, "", "  ")
+	indented.WriteByte('\n')
+
+	return AssertGolden(tb, path, indented.Bytes())
+}
+
+// lineDiff returns the lines of want and got which differ, prefixed with - and +
+func lineDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	var b strings.Builder
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+
+		if w == g {
+			continue
+		}
+		if i < len(wantLines) {
+			fmt.Fprintf(&b, "line %d\n- %s\n", i+1, w)
+		} else {
+			fmt.Fprintf(&b, "line %d\n", i+1)
+		}
+		if i < len(gotLines) {
+			fmt.Fprintf(&b, "+ %s\n", g)
+		}
+	}
+
+	return b.String()
+}
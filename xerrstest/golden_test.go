@@ -0,0 +1,45 @@
+package xerrstest
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "output.golden")
+
+	defer func(value bool) { *update = value }(*update)
+	*update = true
+	if !AssertGolden(t, path, []byte("a\nb\n")) {
+		t.Fatalf("expected the golden file to be written")
+	}
+	*update = false
+
+	r := &recorder{TB: t}
+	if !AssertGolden(r, path, []byte("a\nb\n")) || len(r.failures) != 0 {
+		t.Errorf("unexpected failure: %v", r.failures)
+	}
+
+	if AssertGolden(r, path, []byte("a\nc\n")) {
+		t.Errorf("expected a failure for different output")
+	}
+	if want := "line 2\n- b\n+ c\n"; len(r.failures) != 1 || !strings.HasSuffix(r.failures[0], want) {
+		t.Errorf("wrong failure: want suffix=%q got=%q", want, r.failures)
+	}
+}
+
+func newGoldenError() error {
+	err := xerrs.Wrap(xerrs.New("connection refused", xerrs.WithData("host", "db1")), "load shipment")
+	return xerrs.Mask(err, errors.New("try again later"))
+}
+
+func TestAssertGoldenOutput(t *testing.T) {
+	err := newGoldenError()
+
+	AssertGoldenDetails(t, "testdata/details.golden", err)
+	AssertGoldenJSON(t, "testdata/json.golden", err)
+}
@@ -0,0 +1,122 @@
+// Package xerrstest provides test assertions for xerrs errors. Every assertion
+// reports the difference and the Details of the error on failure, and returns
+// whether it passed.
+package xerrstest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+// MaxStack - the number of stack locations printed with the Details of a failing error
+var MaxStack = 10
+
+// AssertCause - checks that the message of the root cause of err, the
+// innermost error of the chain, is want
+func AssertCause(tb testing.TB, err error, want string) bool {
+	tb.Helper()
+
+	if err == nil {
+		return fail(tb, err, "expected an error with cause %q, got nil", want)
+	}
+
+	root := err
+	for next := errors.Unwrap(root); next != nil; next = errors.Unwrap(root) {
+		root = next
+	}
+
+	if got := root.Error(); got != want {
+		return fail(tb, err, "wrong cause\n\twant: %q\n\tgot:  %q", want, got)
+	}
+
+	return true
+}
+
+// AssertMasked - checks that err is masked with mask, so that Error returns
+// the message of mask
+func AssertMasked(tb testing.TB, err, mask error) bool {
+	tb.Helper()
+
+	if err == nil {
+		return fail(tb, err, "expected an error masked with %q, got nil", mask)
+	}
+
+	if got := err.Error(); got != mask.Error() {
+		return fail(tb, err, "wrong mask\n\twant: %q\n\tgot:  %q", mask.Error(), got)
+	}
+
+	return true
+}
+
+// AssertData - checks that the data of err stored at key is equal to want,
+// compared with reflect.DeepEqual
+func AssertData(tb testing.TB, err error, key string, want interface{}) bool {
+	tb.Helper()
+
+	got, ok := xerrs.GetData(err, key)
+	if !ok {
+		return fail(tb, err, "missing data %q\n\twant: %#v", key, want)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		return fail(tb, err, "wrong data %q\n\twant: %#v\n\tgot:  %#v", key, want, got)
+	}
+
+	return true
+}
+
+// AssertCode - checks that the code of err is want, see xerrs.Code
+func AssertCode(tb testing.TB, err error, want string) bool {
+	tb.Helper()
+
+	if got := xerrs.Code(err); got != want {
+		return fail(tb, err, "wrong code\n\twant: %q\n\tgot:  %q", want, got)
+	}
+
+	return true
+}
+
+// AssertStackContains - checks that the stack of err contains the function.
+// function is either fully qualified, e.g. "github.com/org/app/db.Insert", or
+// ends the fully qualified name after a slash or dot, e.g. "db.Insert".
+func AssertStackContains(tb testing.TB, err error, function string) bool {
+	tb.Helper()
+
+	for _, location := range xerrs.Stack(err) {
+		name := location.Function
+		if name == function || strings.HasSuffix(name, "/"+function) || strings.HasSuffix(name, "."+function) {
+			return true
+		}
+	}
+
+	return fail(tb, err, "stack does not contain %s", function)
+}
+
+// AssertWrapped - checks that err wraps target, like errors.Is
+func AssertWrapped(tb testing.TB, err, target error) bool {
+	tb.Helper()
+
+	if !errors.Is(err, target) {
+		return fail(tb, err, "error does not wrap %q", target)
+	}
+
+	return true
+}
+
+// fail reports the failure followed by the Details of err
+func fail(tb testing.TB, err error, format string, args ...interface{}) bool {
+	tb.Helper()
+
+	details := "<nil>"
+	if err != nil {
+		details = strings.TrimPrefix(xerrs.Details(err, MaxStack), "\n")
+	}
+
+	tb.Errorf("%s\ndetails:\n%s", fmt.Sprintf(format, args...), details)
+	return false
+}
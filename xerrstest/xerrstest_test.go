@@ -0,0 +1,85 @@
+package xerrstest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/RoseRocket/xerrs"
+)
+
+// recorder collects the failures reported by the assertions
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+var errNotFound = errors.New("not found")
+
+func TestAssertions(t *testing.T) {
+	mask := errors.New("try again later")
+	err := xerrs.Wrap(xerrs.Extend(fmt.Errorf("query: %w", errNotFound), xerrs.WithData("id", 7), xerrs.WithCode("db")), "load")
+	err = xerrs.Mask(err, mask)
+
+	cases := []struct {
+		name    string
+		assert  func(tb testing.TB) bool
+		failure string
+	}{
+		{"cause", func(tb testing.TB) bool { return AssertCause(tb, err, "not found") }, ""},
+		{"wrong cause", func(tb testing.TB) bool { return AssertCause(tb, err, "timeout") }, "wrong cause\n\twant: \"timeout\"\n\tgot:  \"not found\""},
+		{"nil cause", func(tb testing.TB) bool { return AssertCause(tb, nil, "timeout") }, "expected an error with cause \"timeout\", got nil\ndetails:\n<nil>"},
+		{"masked", func(tb testing.TB) bool { return AssertMasked(tb, err, mask) }, ""},
+		{"wrong mask", func(tb testing.TB) bool { return AssertMasked(tb, err, errors.New("oops")) }, "wrong mask\n\twant: \"oops\"\n\tgot:  \"try again later\""},
+		{"data", func(tb testing.TB) bool { return AssertData(tb, err, "id", 7) }, ""},
+		{"wrong data", func(tb testing.TB) bool { return AssertData(tb, err, "id", int64(7)) }, "wrong data \"id\"\n\twant: 7\n\tgot:  7"},
+		{"missing data", func(tb testing.TB) bool { return AssertData(tb, err, "name", "a") }, "missing data \"name\""},
+		{"code", func(tb testing.TB) bool { return AssertCode(tb, err, "db") }, ""},
+		{"wrong code", func(tb testing.TB) bool { return AssertCode(tb, err, "http") }, "wrong code\n\twant: \"http\"\n\tgot:  \"db\""},
+		{"stack", func(tb testing.TB) bool { return AssertStackContains(tb, err, "xerrstest.TestAssertions") }, ""},
+		{"qualified stack", func(tb testing.TB) bool {
+			return AssertStackContains(tb, err, "github.com/RoseRocket/xerrs/xerrstest.TestAssertions")
+		}, ""},
+		{"wrong stack", func(tb testing.TB) bool { return AssertStackContains(tb, err, "Assertions") }, "stack does not contain Assertions"},
+		{"wrapped", func(tb testing.TB) bool { return AssertWrapped(tb, err, errNotFound) }, ""},
+		{"not wrapped", func(tb testing.TB) bool { return AssertWrapped(tb, err, mask) }, "error does not wrap \"try again later\""},
+	}
+
+	for _, c := range cases {
+		r := &recorder{TB: t}
+		ok := c.assert(r)
+
+		if ok != (c.failure == "") {
+			t.Errorf("wrong result for %s: want=%v got=%v", c.name, c.failure == "", ok)
+		}
+
+		if c.failure == "" {
+			if len(r.failures) != 0 {
+				t.Errorf("unexpected failure for %s: %v", c.name, r.failures)
+			}
+			continue
+		}
+
+		if len(r.failures) != 1 || !strings.HasPrefix(r.failures[0], c.failure) {
+			t.Errorf("wrong failure for %s: want prefix=%q got=%q", c.name, c.failure, r.failures)
+		}
+	}
+}
+
+func TestFailureDetails(t *testing.T) {
+	r := &recorder{TB: t}
+	err := xerrs.New("ABC")
+	AssertCode(r, err, "db")
+
+	want := "\ndetails:\n[ERROR] ABC\n[STACK]:\ngithub.com/RoseRocket/xerrs/xerrstest.TestFailureDetails ["
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], want) {
+		t.Errorf("expected failure to contain %q, got=%q", want, r.failures)
+	}
+}